@@ -0,0 +1,126 @@
+// Command export dumps news_articles or user_events as CSV or NDJSON, with the
+// same filters as the HTTP export endpoints.
+//
+//	go run ./cmd/export -collection articles -format csv -category sports -out sports.csv
+//	go run ./cmd/export -collection events -format ndjson -from 2025-08-01
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"news-api/internal/config"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/services"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// optionalFloat is a float flag that records whether it was set.
+type optionalFloat struct{ value *float64 }
+
+func (f *optionalFloat) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f.value, 'f', -1, 64)
+}
+
+func (f *optionalFloat) Set(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	f.value = &v
+	return nil
+}
+
+func main() {
+	collection := flag.String("collection", "articles", "what to export: articles or events")
+	format := flag.String("format", services.ExportFormatNDJSON, "output format: csv or ndjson")
+	out := flag.String("out", "", "output file (default stdout)")
+	includeEmbedding := flag.Bool("include-embedding", false, "include vector_embedding in article exports")
+	configFile := flag.String("config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+
+	var newsParams dto.NewsFilterParams
+	flag.StringVar(&newsParams.Category, "category", "", "article category")
+	flag.StringVar(&newsParams.Source, "source", "", "article source name")
+	flag.StringVar(&newsParams.Query, "q", "", "keyword in title or description")
+	var minScore, lat, lon, radius optionalFloat
+	flag.Var(&minScore, "min-score", "minimum relevance score")
+	flag.Var(&lat, "lat", "latitude for a nearby filter")
+	flag.Var(&lon, "lon", "longitude for a nearby filter")
+	flag.Var(&radius, "radius", "radius in km for a nearby filter")
+
+	var eventParams dto.EventFilterParams
+	flag.StringVar(&eventParams.ArticleID, "article-id", "", "event article id")
+	flag.StringVar(&eventParams.UserID, "user-id", "", "event user id")
+	flag.StringVar(&eventParams.EventType, "event-type", "", "event type: view, click or share")
+
+	from := flag.String("from", "", "start of the publication date (articles) or timestamp (events) range")
+	to := flag.String("to", "", "end of the publication date (articles) or timestamp (events) range")
+	flag.Parse()
+
+	newsParams.MinScore, newsParams.Lat, newsParams.Lon, newsParams.Radius = minScore.value, lat.value, lon.value, radius.value
+	newsParams.From, newsParams.To = *from, *to
+	eventParams.From, eventParams.To = *from, *to
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+	var configArgs []string
+	if *configFile != "" {
+		configArgs = []string{"-config", *configFile}
+	}
+	cfg, err := config.Load(configArgs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	services.Configure(cfg)
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatal("Failed to create output file: ", err)
+		}
+		defer file.Close()
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+	defer buffered.Flush()
+
+	if err := database.Connect(cfg.Mongo); err != nil {
+		log.Fatal(err)
+	}
+
+	var count int
+	var filter primitive.M
+	switch *collection {
+	case "articles":
+		if filter, err = services.BuildNewsFilter(newsParams); err != nil {
+			log.Fatal("Invalid filter: ", err)
+		}
+		count, err = services.ExportArticles(context.Background(), buffered, filter, *format, *includeEmbedding)
+	case "events":
+		if filter, err = services.BuildEventFilter(eventParams); err != nil {
+			log.Fatal("Invalid filter: ", err)
+		}
+		count, err = services.ExportEvents(context.Background(), buffered, filter, *format)
+	default:
+		log.Fatal("Unknown collection: ", *collection)
+	}
+	if err != nil {
+		buffered.Flush()
+		log.Fatalf("Export failed after %d rows: %v", count, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d %s\n", count, *collection)
+}
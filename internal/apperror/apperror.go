@@ -0,0 +1,121 @@
+// Package apperror defines the typed errors services and handlers return to
+// clients. Each error has a kind, which decides the HTTP status code, and a
+// message that is safe to show; the cause is only logged.
+package apperror
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Kind is the machine-readable code of an error, sent as "code".
+type Kind string
+
+const (
+	KindValidation          Kind = "validation"
+	KindUnauthorized        Kind = "unauthorized"
+	KindForbidden           Kind = "forbidden"
+	KindNotFound            Kind = "not_found"
+	KindConflict            Kind = "conflict"
+	KindRateLimited         Kind = "rate_limited"
+	KindUpstreamUnavailable Kind = "upstream_unavailable"
+	KindInternal            Kind = "internal"
+)
+
+// Status returns the HTTP status code of errors of this kind.
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	case KindUpstreamUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FieldError describes one invalid field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is an error with a kind and a client-safe message.
+type Error struct {
+	Kind    Kind
+	Message string
+	Details []FieldError
+	// Err is the underlying cause. It is logged but never sent to clients.
+	Err error
+
+	// base is the error Errorf added detail to, so errors.Is still matches it.
+	base *Error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	return e.base != nil && (e.base == target || e.base.Is(target))
+}
+
+// New returns an error of the given kind.
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap returns an error of the given kind caused by err.
+func Wrap(kind Kind, message string, err error) *Error {
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// Errorf returns an error of base's kind whose message adds the formatted
+// detail to base's, e.g. "invalid pin: invalid starts_at". errors.Is(err,
+// base) reports true.
+func Errorf(base *Error, format string, args ...interface{}) *Error {
+	return &Error{
+		Kind:    base.Kind,
+		Message: base.Message + ": " + fmt.Sprintf(format, args...),
+		base:    base,
+	}
+}
+
+func NotFound(message string) *Error {
+	return New(KindNotFound, message)
+}
+
+func Conflict(message string) *Error {
+	return New(KindConflict, message)
+}
+
+// Validation returns a validation error, optionally listing the invalid
+// fields.
+func Validation(message string, details ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Message: message, Details: details}
+}
+
+func Upstream(message string, err error) *Error {
+	return Wrap(KindUpstreamUnavailable, message, err)
+}
+
+func Internal(message string, err error) *Error {
+	return Wrap(KindInternal, message, err)
+}
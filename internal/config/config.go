@@ -0,0 +1,567 @@
+// Package config loads the typed application configuration. Values come from
+// defaults, then an optional YAML or TOML file, then environment variables,
+// then command-line flags, each overriding the previous one.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration read from strings such as "90s" or "6h" in
+// config files and environment variables.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.Duration.String()), nil
+}
+
+type ServerConfig struct {
+	Port int `yaml:"port" toml:"port"`
+	// ShutdownTimeout bounds how long shutdown waits for in-flight requests
+	// and running jobs.
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// TrustedProxies are the IPs and CIDRs of reverse proxies whose
+	// X-Forwarded-For header gives the client IP. Without any, the client IP
+	// is the address of the connection.
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+}
+
+// Addr is the listen address of the HTTP server.
+func (s ServerConfig) Addr() string {
+	return ":" + strconv.Itoa(s.Port)
+}
+
+type MongoConfig struct {
+	URI                string   `yaml:"uri" toml:"uri"`
+	Database           string   `yaml:"database" toml:"database"`
+	ArticlesCollection string   `yaml:"articles_collection" toml:"articles_collection"`
+	ConnectTimeout     Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+}
+
+// ArchiveCollection is the collection receiving archived articles.
+func (m MongoConfig) ArchiveCollection() string {
+	return m.ArticlesCollection + "_archive"
+}
+
+type RedisConfig struct {
+	Endpoint string `yaml:"endpoint" toml:"endpoint"`
+	Password string `yaml:"password" toml:"password"`
+	DB       int    `yaml:"db" toml:"db"`
+}
+
+type GeminiConfig struct {
+	// Only the smart router needs a key; it is optional at startup.
+	APIKey string `yaml:"api_key" toml:"api_key"`
+	Model  string `yaml:"model" toml:"model"`
+}
+
+// EnrichmentConfig points at the embedding and summarization sidecar.
+type EnrichmentConfig struct {
+	BaseURL string   `yaml:"base_url" toml:"base_url"`
+	Timeout Duration `yaml:"timeout" toml:"timeout"`
+}
+
+// CronConfig holds the schedules of the background jobs, in cron syntax or
+// descriptors such as "@hourly" and "@every 15m".
+type CronConfig struct {
+	Trending   string `yaml:"trending" toml:"trending"`
+	Clustering string `yaml:"clustering" toml:"clustering"`
+	Feeds      string `yaml:"feeds" toml:"feeds"`
+	Publish    string `yaml:"publish" toml:"publish"`
+	Retention  string `yaml:"retention" toml:"retention"`
+}
+
+type TrendingConfig struct {
+	// Windows maps the names accepted by ?window= to their durations.
+	Windows  map[string]Duration `yaml:"windows" toml:"windows"`
+	Limit    int                 `yaml:"limit" toml:"limit"`
+	CacheTTL Duration            `yaml:"cache_ttl" toml:"cache_ttl"`
+}
+
+// LongestWindow is the largest trending window.
+func (t TrendingConfig) LongestWindow() time.Duration {
+	var longest time.Duration
+	for _, window := range t.Windows {
+		if window.Duration > longest {
+			longest = window.Duration
+		}
+	}
+	return longest
+}
+
+type AuthConfig struct {
+	// Enabled requires an API key on /api/v1 routes.
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// BootstrapKey is an admin key that is not stored in MongoDB, used to
+	// create the first keys.
+	BootstrapKey string `yaml:"bootstrap_key" toml:"bootstrap_key"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Window is the length of the sliding window the limits apply to.
+	Window Duration       `yaml:"window" toml:"window"`
+	PerKey RateLimitRules `yaml:"per_key" toml:"per_key"`
+	PerIP  RateLimitRules `yaml:"per_ip" toml:"per_ip"`
+}
+
+// RateLimitRules are the requests allowed per window for each budget.
+// Expensive routes count against both budgets.
+type RateLimitRules struct {
+	Default   int `yaml:"default" toml:"default"`
+	Expensive int `yaml:"expensive" toml:"expensive"`
+}
+
+type IdempotencyConfig struct {
+	// Enabled honours Idempotency-Key headers on the routes that accept them.
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// TTL is how long a response is kept for replay.
+	TTL Duration `yaml:"ttl" toml:"ttl"`
+}
+
+type LoggingConfig struct {
+	// Level is debug, info, warn or error.
+	Level string `yaml:"level" toml:"level"`
+	// Format is json or text.
+	Format string `yaml:"format" toml:"format"`
+}
+
+type TracingConfig struct {
+	// Exporter is none, stdout or otlp. The OTLP endpoint is read from the
+	// standard OTEL_EXPORTER_OTLP_ENDPOINT variable.
+	Exporter    string  `yaml:"exporter" toml:"exporter"`
+	ServiceName string  `yaml:"service_name" toml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"`
+}
+
+type HealthConfig struct {
+	// Timeout bounds each dependency check of /readyz.
+	Timeout Duration `yaml:"timeout" toml:"timeout"`
+}
+
+type RetentionConfig struct {
+	// Raw user events older than this many days are removed once rolled up.
+	EventRetentionDays int `yaml:"event_retention_days" toml:"event_retention_days"`
+	// Use a TTL index on user_events.timestamp instead of scheduled pruning.
+	EventTTL bool `yaml:"event_ttl" toml:"event_ttl"`
+	// Articles published more than this many months ago are archived; 0
+	// disables archival.
+	ArchiveAfterMonths int `yaml:"archive_after_months" toml:"archive_after_months"`
+}
+
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	Mongo       MongoConfig       `yaml:"mongo" toml:"mongo"`
+	Redis       RedisConfig       `yaml:"redis" toml:"redis"`
+	Gemini      GeminiConfig      `yaml:"gemini" toml:"gemini"`
+	Enrichment  EnrichmentConfig  `yaml:"enrichment" toml:"enrichment"`
+	Cron        CronConfig        `yaml:"cron" toml:"cron"`
+	Trending    TrendingConfig    `yaml:"trending" toml:"trending"`
+	Retention   RetentionConfig   `yaml:"retention" toml:"retention"`
+	Health      HealthConfig      `yaml:"health" toml:"health"`
+	Tracing     TracingConfig     `yaml:"tracing" toml:"tracing"`
+	Logging     LoggingConfig     `yaml:"logging" toml:"logging"`
+	Auth        AuthConfig        `yaml:"auth" toml:"auth"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" toml:"rate_limit"`
+	Idempotency IdempotencyConfig `yaml:"idempotency" toml:"idempotency"`
+}
+
+// Default returns the configuration used when nothing overrides it.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{Port: 8080, ShutdownTimeout: Duration{30 * time.Second}},
+		Mongo: MongoConfig{
+			ArticlesCollection: "news_articles",
+			ConnectTimeout:     Duration{10 * time.Second},
+		},
+		Redis:  RedisConfig{Endpoint: "localhost:6379"},
+		Gemini: GeminiConfig{Model: "gemini-2.5-flash"},
+		Enrichment: EnrichmentConfig{
+			BaseURL: "http://localhost:8001",
+			Timeout: Duration{60 * time.Second},
+		},
+		Cron: CronConfig{
+			Trending:   "@hourly",
+			Clustering: "@every 30m",
+			Feeds:      "@every 15m",
+			Publish:    "@every 1m",
+			Retention:  "@daily",
+		},
+		Trending: TrendingConfig{
+			Windows: map[string]Duration{
+				"6h":   {6 * time.Hour},
+				"24h":  {24 * time.Hour},
+				"week": {7 * 24 * time.Hour},
+			},
+			Limit:    10,
+			CacheTTL: Duration{time.Hour},
+		},
+		Retention: RetentionConfig{EventRetentionDays: 30, ArchiveAfterMonths: 12},
+		Health:    HealthConfig{Timeout: Duration{2 * time.Second}},
+		Tracing:   TracingConfig{Exporter: "none", ServiceName: "news-api", SampleRatio: 1},
+		Logging:   LoggingConfig{Level: "info", Format: "json"},
+		Auth:      AuthConfig{Enabled: true},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Window:  Duration{time.Minute},
+			PerKey:  RateLimitRules{Default: 600, Expensive: 60},
+			PerIP:   RateLimitRules{Default: 300, Expensive: 30},
+		},
+		Idempotency: IdempotencyConfig{Enabled: true, TTL: Duration{24 * time.Hour}},
+	}
+}
+
+// Load builds the configuration from defaults, the file named by -config or
+// CONFIG_FILE, the environment and the flags in args, and validates it.
+// args are the command-line arguments without the program name.
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("news-api", flag.ContinueOnError)
+	configFile := fs.String("config", "", "path to a YAML or TOML config file (env CONFIG_FILE)")
+	port := fs.Int("port", 0, "HTTP listen port (env PORT)")
+	mongoURI := fs.String("mongodb-uri", "", "MongoDB connection string (env MONGODB_URI)")
+	database := fs.String("database", "", "MongoDB database name (env DATABASE_NAME)")
+	redisEndpoint := fs.String("redis-endpoint", "", "Redis host:port (env REDIS_ENDPOINT)")
+	enrichmentURL := fs.String("enrichment-url", "", "base URL of the embedding/summarization service (env ENRICHMENT_URL)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	path := os.Getenv("CONFIG_FILE")
+	if *configFile != "" {
+		path = *configFile
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	// Only flags given on the command line override earlier sources.
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = *port
+		case "mongodb-uri":
+			cfg.Mongo.URI = *mongoURI
+		case "database":
+			cfg.Mongo.Database = *database
+		case "redis-endpoint":
+			cfg.Redis.Endpoint = *redisEndpoint
+		case "enrichment-url":
+			cfg.Enrichment.BaseURL = *enrichmentURL
+		}
+	})
+	cfg.Enrichment.BaseURL = strings.TrimRight(cfg.Enrichment.BaseURL, "/")
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// Windows in the file replace the defaults rather than adding to them.
+	windows := c.Trending.Windows
+	c.Trending.Windows = nil
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".toml":
+		err = toml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("unsupported config file %q: use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if c.Trending.Windows == nil {
+		c.Trending.Windows = windows
+	}
+	return nil
+}
+
+// loadEnv applies the environment variables that are set. Every malformed
+// variable is reported, not only the first.
+func (c *Config) loadEnv() error {
+	var errs []error
+	str := func(target *string, name string) {
+		if value, ok := os.LookupEnv(name); ok {
+			*target = value
+		}
+	}
+	integer := func(target *int, name string) {
+		if value, ok := os.LookupEnv(name); ok {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s must be an integer, got %q", name, value))
+				return
+			}
+			*target = parsed
+		}
+	}
+	boolean := func(target *bool, name string) {
+		if value, ok := os.LookupEnv(name); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s must be a boolean, got %q", name, value))
+				return
+			}
+			*target = parsed
+		}
+	}
+	float := func(target *float64, name string) {
+		if value, ok := os.LookupEnv(name); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s must be a number, got %q", name, value))
+				return
+			}
+			*target = parsed
+		}
+	}
+	duration := func(target *Duration, name string) {
+		if value, ok := os.LookupEnv(name); ok {
+			if err := target.UnmarshalText([]byte(value)); err != nil {
+				errs = append(errs, fmt.Errorf("%s must be a duration such as 30s or 1h, got %q", name, value))
+			}
+		}
+	}
+
+	integer(&c.Server.Port, "PORT")
+	duration(&c.Server.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	str(&c.Mongo.URI, "MONGODB_URI")
+	str(&c.Mongo.Database, "DATABASE_NAME")
+	str(&c.Mongo.ArticlesCollection, "COLLECTION_NAME")
+	duration(&c.Mongo.ConnectTimeout, "MONGODB_CONNECT_TIMEOUT")
+	str(&c.Redis.Endpoint, "REDIS_ENDPOINT")
+	str(&c.Redis.Password, "REDIS_PASSWORD")
+	integer(&c.Redis.DB, "REDIS_DB")
+	str(&c.Gemini.APIKey, "GEMINI_API_KEY")
+	str(&c.Gemini.Model, "GEMINI_MODEL")
+	str(&c.Enrichment.BaseURL, "ENRICHMENT_URL")
+	duration(&c.Enrichment.Timeout, "ENRICHMENT_TIMEOUT")
+	str(&c.Cron.Trending, "CRON_TRENDING")
+	str(&c.Cron.Clustering, "CRON_CLUSTERING")
+	str(&c.Cron.Feeds, "CRON_FEEDS")
+	str(&c.Cron.Publish, "CRON_PUBLISH")
+	str(&c.Cron.Retention, "CRON_RETENTION")
+	integer(&c.Trending.Limit, "TRENDING_LIMIT")
+	duration(&c.Trending.CacheTTL, "TRENDING_CACHE_TTL")
+	integer(&c.Retention.EventRetentionDays, "EVENT_RETENTION_DAYS")
+	boolean(&c.Retention.EventTTL, "EVENT_RETENTION_TTL")
+	integer(&c.Retention.ArchiveAfterMonths, "ARTICLE_ARCHIVE_MONTHS")
+	duration(&c.Health.Timeout, "HEALTH_CHECK_TIMEOUT")
+	str(&c.Tracing.Exporter, "TRACING_EXPORTER")
+	str(&c.Tracing.ServiceName, "OTEL_SERVICE_NAME")
+	float(&c.Tracing.SampleRatio, "TRACING_SAMPLE_RATIO")
+	str(&c.Logging.Level, "LOG_LEVEL")
+	str(&c.Logging.Format, "LOG_FORMAT")
+	boolean(&c.Auth.Enabled, "AUTH_ENABLED")
+	str(&c.Auth.BootstrapKey, "AUTH_BOOTSTRAP_KEY")
+	boolean(&c.RateLimit.Enabled, "RATE_LIMIT_ENABLED")
+	duration(&c.RateLimit.Window, "RATE_LIMIT_WINDOW")
+	integer(&c.RateLimit.PerKey.Default, "RATE_LIMIT_KEY_DEFAULT")
+	integer(&c.RateLimit.PerKey.Expensive, "RATE_LIMIT_KEY_EXPENSIVE")
+	integer(&c.RateLimit.PerIP.Default, "RATE_LIMIT_IP_DEFAULT")
+	integer(&c.RateLimit.PerIP.Expensive, "RATE_LIMIT_IP_EXPENSIVE")
+	boolean(&c.Idempotency.Enabled, "IDEMPOTENCY_ENABLED")
+	duration(&c.Idempotency.TTL, "IDEMPOTENCY_TTL")
+
+	// TRUSTED_PROXIES is a list of IPs and CIDRs, e.g. "10.0.0.0/8,192.168.1.2".
+	if value, ok := os.LookupEnv("TRUSTED_PROXIES"); ok {
+		c.Server.TrustedProxies = nil
+		for _, proxy := range strings.Split(value, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				c.Server.TrustedProxies = append(c.Server.TrustedProxies, proxy)
+			}
+		}
+	}
+
+	// TRENDING_WINDOWS is a list of name=duration pairs, e.g. "6h=6h,week=168h".
+	if value, ok := os.LookupEnv("TRENDING_WINDOWS"); ok {
+		windows := make(map[string]Duration)
+		for _, pair := range strings.Split(value, ",") {
+			name, raw, found := strings.Cut(strings.TrimSpace(pair), "=")
+			var window Duration
+			if !found || window.UnmarshalText([]byte(raw)) != nil {
+				errs = append(errs, fmt.Errorf("TRENDING_WINDOWS must be name=duration pairs separated by commas, got %q", pair))
+				continue
+			}
+			windows[name] = window
+		}
+		c.Trending.Windows = windows
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Problems: errs}
+	}
+	return nil
+}
+
+// ValidationError lists every problem found in a configuration.
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, problem := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(problem.Error())
+	}
+	return b.String()
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// minBootstrapKeyLength keeps the bootstrap key from being guessable.
+const minBootstrapKeyLength = 32
+
+var windowNamePattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// Validate checks the configuration and reports all problems at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port (PORT) must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.ShutdownTimeout.Duration > 0, "server.shutdown_timeout (SHUTDOWN_TIMEOUT) must be positive")
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(net.ParseIP(proxy) != nil || cidrErr == nil, "server.trusted_proxies (TRUSTED_PROXIES) must be IPs or CIDRs, got %q", proxy)
+	}
+
+	check(c.Mongo.URI != "", "mongo.uri (MONGODB_URI) is required")
+	check(c.Mongo.Database != "", "mongo.database (DATABASE_NAME) is required")
+	check(c.Mongo.ArticlesCollection != "", "mongo.articles_collection (COLLECTION_NAME) must not be empty")
+	check(c.Mongo.ConnectTimeout.Duration > 0, "mongo.connect_timeout (MONGODB_CONNECT_TIMEOUT) must be positive")
+
+	check(c.Redis.Endpoint != "", "redis.endpoint (REDIS_ENDPOINT) must not be empty")
+	check(c.Redis.DB >= 0, "redis.db (REDIS_DB) must not be negative")
+
+	check(c.Gemini.Model != "", "gemini.model (GEMINI_MODEL) must not be empty")
+
+	if parsed, err := url.Parse(c.Enrichment.BaseURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		errs = append(errs, fmt.Errorf("enrichment.base_url (ENRICHMENT_URL) must be an http(s) URL, got %q", c.Enrichment.BaseURL))
+	}
+	check(c.Enrichment.Timeout.Duration > 0, "enrichment.timeout (ENRICHMENT_TIMEOUT) must be positive")
+
+	schedules := []struct{ name, spec string }{
+		{"cron.trending (CRON_TRENDING)", c.Cron.Trending},
+		{"cron.clustering (CRON_CLUSTERING)", c.Cron.Clustering},
+		{"cron.feeds (CRON_FEEDS)", c.Cron.Feeds},
+		{"cron.publish (CRON_PUBLISH)", c.Cron.Publish},
+		{"cron.retention (CRON_RETENTION)", c.Cron.Retention},
+	}
+	for _, schedule := range schedules {
+		if _, err := cron.ParseStandard(schedule.spec); err != nil {
+			errs = append(errs, fmt.Errorf("%s is not a valid schedule %q: %v", schedule.name, schedule.spec, err))
+		}
+	}
+
+	check(len(c.Trending.Windows) > 0, "trending.windows (TRENDING_WINDOWS) must define at least one window")
+	names := make([]string, 0, len(c.Trending.Windows))
+	for name := range c.Trending.Windows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		check(windowNamePattern.MatchString(name), "trending window name %q must contain only lowercase letters and digits", name)
+		check(c.Trending.Windows[name].Duration > 0, "trending window %q must be positive", name)
+	}
+	check(c.Trending.Limit > 0, "trending.limit (TRENDING_LIMIT) must be positive, got %d", c.Trending.Limit)
+	check(c.Trending.CacheTTL.Duration > 0, "trending.cache_ttl (TRENDING_CACHE_TTL) must be positive")
+
+	// Trending reads raw events, so they must outlive the longest window.
+	minRetentionDays := int(c.Trending.LongestWindow()/(24*time.Hour)) + 1
+	check(c.Retention.EventRetentionDays >= minRetentionDays,
+		"retention.event_retention_days (EVENT_RETENTION_DAYS) must be at least %d to cover the longest trending window, got %d",
+		minRetentionDays, c.Retention.EventRetentionDays)
+	check(c.Retention.ArchiveAfterMonths >= 0, "retention.archive_after_months (ARTICLE_ARCHIVE_MONTHS) must not be negative")
+
+	check(c.Health.Timeout.Duration > 0, "health.timeout (HEALTH_CHECK_TIMEOUT) must be positive")
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter (TRACING_EXPORTER) must be none, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+	check(c.Tracing.ServiceName != "", "tracing.service_name (OTEL_SERVICE_NAME) must not be empty")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+
+	switch strings.ToLower(c.Logging.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("logging.level (LOG_LEVEL) must be debug, info, warn or error, got %q", c.Logging.Level))
+	}
+	check(c.Logging.Format == "json" || c.Logging.Format == "text", "logging.format (LOG_FORMAT) must be json or text, got %q", c.Logging.Format)
+
+	check(c.Auth.BootstrapKey == "" || len(c.Auth.BootstrapKey) >= minBootstrapKeyLength,
+		"auth.bootstrap_key (AUTH_BOOTSTRAP_KEY) must be at least %d characters", minBootstrapKeyLength)
+
+	check(c.RateLimit.Window.Duration >= time.Second, "rate_limit.window (RATE_LIMIT_WINDOW) must be at least 1s")
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"rate_limit.per_key.default (RATE_LIMIT_KEY_DEFAULT)", c.RateLimit.PerKey.Default},
+		{"rate_limit.per_key.expensive (RATE_LIMIT_KEY_EXPENSIVE)", c.RateLimit.PerKey.Expensive},
+		{"rate_limit.per_ip.default (RATE_LIMIT_IP_DEFAULT)", c.RateLimit.PerIP.Default},
+		{"rate_limit.per_ip.expensive (RATE_LIMIT_IP_EXPENSIVE)", c.RateLimit.PerIP.Expensive},
+	}
+	for _, limit := range limits {
+		check(limit.value > 0, "%s must be positive, got %d", limit.name, limit.value)
+	}
+
+	check(c.Idempotency.TTL.Duration >= time.Minute, "idempotency.ttl (IDEMPOTENCY_TTL) must be at least 1m")
+
+	if len(errs) > 0 {
+		return &ValidationError{Problems: errs}
+	}
+	return nil
+}
@@ -3,40 +3,68 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
-	"os"
-	"time"
+	"log/slog"
+	"news-api/internal/config"
+	"news-api/internal/metrics"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 var Client *mongo.Client
 var Database *mongo.Database
 
-func Connect() {
-	os.Getenv("PORT")
-	mongoURI := os.Getenv("MONGODB_URI")
-	databaseName := os.Getenv("DATABASE_NAME")
-
-	secondsToCancel := 10 * time.Second
-	ctx, cancel := context.WithTimeout(context.Background(), secondsToCancel)
+// Connect connects to MongoDB and pings it.
+func Connect(cfg config.MongoConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout.Duration)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.URI).
+		SetMonitor(chainMonitors(metrics.MongoMonitor(), otelmongo.NewMonitor())))
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+		return fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
 	// Ping the database
 	if err := client.Ping(ctx, nil); err != nil {
-		log.Fatal("Failed to ping MongoDB:", err)
+		client.Disconnect(context.Background())
+		return fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
 	Client = client
-	Database = client.Database(databaseName)
+	Database = client.Database(cfg.Database)
+
+	slog.Info("Connected to MongoDB", "database", cfg.Database)
+	return nil
+}
 
-	fmt.Println("✅ Connected to MongoDB!")
+// chainMonitors combines command monitors, since a client takes only one.
+func chainMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m.Started != nil {
+					m.Started(ctx, e)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, e)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m.Failed != nil {
+					m.Failed(ctx, e)
+				}
+			}
+		},
+	}
 }
 
 func GetCollection(collectionName string) *mongo.Collection {
@@ -46,3 +74,11 @@ func GetCollection(collectionName string) *mongo.Collection {
 func GetDB() *mongo.Database {
 	return Database
 }
+
+// Disconnect closes the MongoDB client.
+func Disconnect(ctx context.Context) error {
+	if Client == nil {
+		return nil
+	}
+	return Client.Disconnect(ctx)
+}
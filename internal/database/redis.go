@@ -2,18 +2,46 @@ package database
 
 import (
 	"context"
-	"os"
+	"log/slog"
+	"news-api/internal/config"
+	"news-api/internal/metrics"
+	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
 var Rdb *redis.Client
 var Ctx = context.Background()
 
-func InitRedis() {
+func InitRedis(cfg config.RedisConfig) {
 	Rdb = redis.NewClient(&redis.Options{
-		Addr:     os.Getenv("REDIS_ENDPOINT"),
-		Password: os.Getenv("REDIS_PASSWORD"),
-		DB:       0,
+		Addr:     cfg.Endpoint,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+		// Callers bound commands with context deadlines, e.g. the rate
+		// limiter, which must not wait for the default socket timeouts.
+		ContextTimeoutEnabled: true,
 	})
+	Rdb.AddHook(metrics.RedisHook{})
+	if err := redisotel.InstrumentTracing(Rdb); err != nil {
+		slog.Warn("Failed to instrument Redis tracing", "error", err)
+	}
+
+	// Redis only backs caches, so the API starts without it; /readyz reports it.
+	ctx, cancel := context.WithTimeout(Ctx, 5*time.Second)
+	defer cancel()
+	if err := Rdb.Ping(ctx).Err(); err != nil {
+		slog.Warn("Failed to ping Redis", "endpoint", cfg.Endpoint, "error", err)
+	} else {
+		slog.Info("Connected to Redis", "endpoint", cfg.Endpoint)
+	}
+}
+
+// CloseRedis closes the Redis client.
+func CloseRedis() error {
+	if Rdb == nil {
+		return nil
+	}
+	return Rdb.Close()
 }
@@ -1,6 +1,11 @@
 package dto
 
-import "news-api/internal/models"
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type SearchRequest struct {
 	Query string `json:"query" binding:"required"`
@@ -43,4 +48,46 @@ type AddNewsRequest struct {
 	Latitude        float64  `json:"latitude" binding:"required"`
 	Longitude       float64  `json:"longitude" binding:"required"`
 	LLMSummary      string   `json:"llm_summary,omitempty"`
+
+	// Editorial workflow. Status defaults to published, or to scheduled when
+	// publish_at lies in the future.
+	Status    string `json:"status,omitempty" binding:"omitempty,oneof=draft scheduled published"`
+	PublishAt string `json:"publish_at,omitempty" binding:"required_if=Status scheduled"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+
+	Breaking bool `json:"breaking,omitempty"`
+}
+
+// Per-item outcomes of a bulk ingest.
+const (
+	BulkStatusCreated          = "created"
+	BulkStatusDuplicate        = "duplicate"
+	BulkStatusInvalid          = "invalid"
+	BulkStatusEnrichmentFailed = "enrichment_failed"
+	BulkStatusFailed           = "failed"
+)
+
+// BulkIngestItem is one entry of a bulk ingest body. Errors holds validation
+// failures found while parsing; items with errors are reported as invalid.
+type BulkIngestItem struct {
+	Index   int
+	Request *AddNewsRequest
+	Errors  []string
+}
+
+type BulkItemResult struct {
+	Index        int                 `json:"index"`
+	Status       string              `json:"status"`
+	ID           *primitive.ObjectID `json:"id,omitempty"`
+	URL          string              `json:"url,omitempty"`
+	CanonicalURL string              `json:"canonical_url,omitempty"`
+	ExistingID   *primitive.ObjectID `json:"existing_id,omitempty"`  // set for duplicates already stored
+	DuplicateOf  *primitive.ObjectID `json:"duplicate_of,omitempty"` // near-duplicate link of a created article
+	Code         apperror.Kind       `json:"code,omitempty"`         // error kind of items that were not stored
+	Errors       []string            `json:"errors,omitempty"`
+}
+
+type BulkIngestResponse struct {
+	Results []BulkItemResult `json:"results"`
+	Summary map[string]int   `json:"summary"`
 }
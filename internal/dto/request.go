@@ -4,3 +4,105 @@ type HelloPostRequest struct {
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
 }
+
+type AddFeedRequest struct {
+	URL            string   `json:"url" binding:"required,url"`
+	SourceName     string   `json:"source_name"`
+	Category       []string `json:"category"`
+	RelevanceScore float64  `json:"relevance_score"`
+	Latitude       float64  `json:"latitude"`
+	Longitude      float64  `json:"longitude"`
+	Enabled        *bool    `json:"enabled,omitempty"`
+}
+
+// NewsFilterParams are the article filters shared by listings, exports and
+// facets. Pointer fields are optional.
+type NewsFilterParams struct {
+	Category           string   `form:"category"`
+	IncludeDescendants bool     `form:"include_descendants"` // also match subcategories
+	Source             string   `form:"source"`
+	MinScore           *float64 `form:"min_score"`
+	Query              string   `form:"q"`
+	Lat                *float64 `form:"lat"`
+	Lon                *float64 `form:"lon"`
+	Radius             *float64 `form:"radius"` // km
+	From               string   `form:"from"`   // publication date lower bound
+	To                 string   `form:"to"`     // publication date upper bound
+}
+
+// EventFilterParams filter user events for exports.
+type EventFilterParams struct {
+	ArticleID string `form:"article_id"`
+	UserID    string `form:"user_id"`
+	EventType string `form:"event_type"`
+	From      string `form:"from"`
+	To        string `form:"to"`
+}
+
+type UpsertCategoryRequest struct {
+	Slug        string   `json:"slug" binding:"required"`
+	DisplayName string   `json:"display_name" binding:"required"`
+	Aliases     []string `json:"aliases"`
+	Parent      string   `json:"parent"`
+}
+
+type UpsertSourceRequest struct {
+	ID          string   `json:"id" binding:"required"`
+	DisplayName string   `json:"display_name" binding:"required"`
+	Aliases     []string `json:"aliases"`
+	Domains     []string `json:"domains"`
+	Country     string   `json:"country"`
+	Language    string   `json:"language"`
+	LogoURL     string   `json:"logo_url" binding:"omitempty,url"`
+	// TrustWeight and Blocked keep their stored values when omitted.
+	TrustWeight *float64 `json:"trust_weight" binding:"omitempty,gte=0,lte=2"`
+	Blocked     *bool    `json:"blocked"`
+}
+
+// ArticleStatusRequest moves an article through the editorial workflow.
+// publish_at is required for scheduled; an empty expires_at clears it.
+type ArticleStatusRequest struct {
+	Status    string  `json:"status" binding:"required,oneof=draft scheduled published archived"`
+	PublishAt string  `json:"publish_at" binding:"required_if=Status scheduled"`
+	ExpiresAt *string `json:"expires_at"`
+}
+
+// UpdateArticleRequest edits an article. Omitted fields are left unchanged.
+type UpdateArticleRequest struct {
+	Title          *string  `json:"title" binding:"omitempty,min=1"`
+	Description    *string  `json:"description" binding:"omitempty,min=1"`
+	Category       []string `json:"category" binding:"omitempty,min=1"`
+	RelevanceScore *float64 `json:"relevance_score" binding:"omitempty,gte=0"`
+	LLMSummary     *string  `json:"llm_summary"`
+}
+
+// AddPinRequest pins an article to a category or the trending feed.
+// starts_at defaults to now; without ends_at the pin stays until deleted.
+type AddPinRequest struct {
+	ArticleID string `json:"article_id" binding:"required"`
+	Feed      string `json:"feed" binding:"required,oneof=category trending"`
+	Category  string `json:"category" binding:"required_if=Feed category"`
+	Region    string `json:"region"`
+	Position  int    `json:"position"`
+	StartsAt  string `json:"starts_at"`
+	EndsAt    string `json:"ends_at"`
+}
+
+// CreateAPIKeyRequest creates an API key. Without expires_at the key does
+// not expire.
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	Scopes    []string `json:"scopes" binding:"required,min=1,dive,oneof=read ingest events admin"`
+	ExpiresAt string   `json:"expires_at"`
+}
+
+type BreakingRequest struct {
+	Breaking *bool `json:"breaking" binding:"required"`
+}
+
+// UpdateSourceTrustRequest changes how a source is ranked. Omitted fields
+// are left unchanged.
+type UpdateSourceTrustRequest struct {
+	TrustWeight *float64 `json:"trust_weight" binding:"omitempty,gte=0,lte=2"`
+	Blocked     *bool    `json:"blocked"`
+}
@@ -19,16 +19,58 @@ type HelloPostResponse struct {
 }
 
 type NewsArticleResponse struct {
+	ID              primitive.ObjectID  `json:"id"`
+	Title           string              `json:"title"`
+	Description     string              `json:"description"`
+	URL             string              `json:"url"`
+	CanonicalURL    string              `json:"canonical_url,omitempty"`
+	PublicationDate time.Time           `json:"publication_date"`
+	SourceName      string              `json:"source_name"`
+	SourceID        string              `json:"source_id,omitempty"`
+	Category        []string            `json:"category"`
+	RelevanceScore  float64             `json:"relevance_score"`
+	RankingScore    float64             `json:"ranking_score"`
+	Location        models.Location     `json:"location"`
+	LLMSummary      string              `json:"llm_summary"`
+	DuplicateOf     *primitive.ObjectID `json:"duplicate_of,omitempty"`
+	StoryID         *primitive.ObjectID `json:"story_id,omitempty"`
+	AlsoReportedBy  []AlsoReportedBy    `json:"also_reported_by,omitempty"`
+	Status          string              `json:"status,omitempty"`
+	PublishAt       *time.Time          `json:"publish_at,omitempty"`
+	ExpiresAt       *time.Time          `json:"expires_at,omitempty"`
+	Breaking        bool                `json:"breaking,omitempty"`
+	Pinned          bool                `json:"pinned,omitempty"`
+	Archived        bool                `json:"archived,omitempty"`
+}
+
+// NewNewsArticleResponseFromTrending converts an enriched trending entry so
+// trending lists can be rendered like any other article listing.
+func NewNewsArticleResponseFromTrending(article models.TrendingArticle) NewsArticleResponse {
+	id, _ := primitive.ObjectIDFromHex(article.ArticleID)
+	response := NewsArticleResponse{
+		ID:              id,
+		Title:           article.Title,
+		Description:     article.Description,
+		URL:             article.URL,
+		PublicationDate: article.PublicationDate,
+		SourceName:      article.SourceName,
+		SourceID:        article.SourceID,
+		LLMSummary:      article.LLMSummary,
+		Pinned:          article.Pinned,
+	}
+	if article.Category != "" {
+		response.Category = []string{article.Category}
+	}
+	return response
+}
+
+// AlsoReportedBy describes a near-duplicate article that was collapsed into
+// its representative in a listing.
+type AlsoReportedBy struct {
 	ID              primitive.ObjectID `json:"id"`
-	Title           string             `json:"title"`
-	Description     string             `json:"description"`
+	SourceName      string             `json:"source_name"`
 	URL             string             `json:"url"`
 	PublicationDate time.Time          `json:"publication_date"`
-	SourceName      string             `json:"source_name"`
-	Category        []string           `json:"category"`
-	RelevanceScore  float64            `json:"relevance_score"`
-	Location        models.Location    `json:"location"`
-	LLMSummary      string             `json:"llm_summary"`
 }
 
 func NewNewsArticleResponse(article models.Article) NewsArticleResponse {
@@ -42,11 +84,87 @@ func NewNewsArticleResponse(article models.Article) NewsArticleResponse {
 		Title:           article.Title,
 		Description:     article.Description,
 		URL:             article.URL,
+		CanonicalURL:    article.CanonicalURL,
 		PublicationDate: article.PublicationDate,
 		SourceName:      article.SourceName,
+		SourceID:        article.SourceID,
 		Category:        article.Category,
 		RelevanceScore:  article.RelevanceScore,
+		RankingScore:    article.RankingScore,
 		Location:        article.Location, // This will now work because models.Location is used
 		LLMSummary:      llmSummary,
+		DuplicateOf:     article.DuplicateOf,
+		StoryID:         article.StoryID,
+		Status:          article.Status,
+		PublishAt:       article.PublishAt,
+		ExpiresAt:       article.ExpiresAt,
+		Breaking:        article.Breaking,
+		Archived:        article.Archived,
 	}
 }
+
+// StoryTimelineResponse is a story with its member articles ordered by
+// publication date.
+type StoryTimelineResponse struct {
+	Story    models.Story          `json:"story"`
+	Articles []NewsArticleResponse `json:"articles"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type ScoreBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// NewsFacets holds counts of the articles matching a filter, broken down by
+// category, source, publication date and relevance score.
+type NewsFacets struct {
+	Total           int           `json:"total"`
+	Category        []FacetCount  `json:"category"`
+	Source          []FacetCount  `json:"source"`
+	PublicationDate []FacetCount  `json:"publication_date"`
+	RelevanceScore  []ScoreBucket `json:"relevance_score"`
+}
+
+// CategoryNode is a taxonomy category with its children, for tree listings.
+type CategoryNode struct {
+	models.Category
+	Children []CategoryNode `json:"children,omitempty"`
+}
+
+// SourceSummary is a source with the number of articles attributed to it.
+// Source names that no registry entry resolves are listed with an empty ID.
+type SourceSummary struct {
+	models.Source
+	Registered   bool  `json:"registered"`
+	ArticleCount int64 `json:"article_count"`
+}
+
+// CreatedAPIKeyResponse is a new API key with its secret, which is returned
+// only this once.
+type CreatedAPIKeyResponse struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+// DependencyHealth is the result of checking one dependency.
+type DependencyHealth struct {
+	Name string `json:"name"`
+	// up, down or unconfigured
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// HealthReport is the readiness of the service and its dependencies.
+type HealthReport struct {
+	// ok, degraded (an optional dependency is down) or unavailable
+	Status       string             `json:"status"`
+	Dependencies []DependencyHealth `json:"dependencies"`
+	CheckedAt    time.Time          `json:"checked_at"`
+}
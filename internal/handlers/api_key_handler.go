@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// POST /admin/api-keys
+func CreateAPIKey(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+
+	key, err := services.CreateAPIKey(&req, getActor(c))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to create API key", err))
+		return
+	}
+
+	utils.SuccessResponse(c, key)
+}
+
+// GET /admin/api-keys
+func GetAPIKeys(c *gin.Context) {
+	keys, err := services.GetAPIKeys()
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve API keys", err))
+		return
+	}
+
+	utils.SuccessResponse(c, keys)
+}
+
+// POST /admin/api-keys/:id/rotate
+func RotateAPIKey(c *gin.Context) {
+	keyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid API key id"))
+		return
+	}
+
+	key, err := services.RotateAPIKey(keyID, getActor(c))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to rotate API key", err))
+		return
+	}
+
+	utils.SuccessResponse(c, key)
+}
+
+// DELETE /admin/api-keys/:id
+func RevokeAPIKey(c *gin.Context) {
+	keyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid API key id"))
+		return
+	}
+
+	err = services.RevokeAPIKey(keyID)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to revoke API key", err))
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"revoked": keyID.Hex()})
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/middleware"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	// Items are deduplicated and inserted in chunks of this size.
+	bulkChunkSize = 100
+	// Longest accepted NDJSON line.
+	maxNDJSONLineSize = 1 << 20
+)
+
+// POST /news/bulk
+//
+// Accepts either a JSON array or an NDJSON body (Content-Type
+// application/x-ndjson) of AddNewsRequest objects and reports a status for
+// every item. NDJSON bodies get an NDJSON response streamed chunk by chunk.
+func CreateNewsEntriesBulk(c *gin.Context) {
+	switch c.ContentType() {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		streamNDJSONBulk(c)
+		return
+	}
+
+	var rawItems []json.RawMessage
+	if err := json.NewDecoder(c.Request.Body).Decode(&rawItems); err != nil {
+		utils.Error(c, apperror.Validation("Invalid input: body must be a JSON array or NDJSON: "+err.Error()))
+		return
+	}
+
+	items := make([]dto.BulkIngestItem, len(rawItems))
+	for i, raw := range rawItems {
+		items[i] = parseBulkItem(c, i, raw)
+	}
+
+	var results []dto.BulkItemResult
+	for start := 0; start < len(items); start += bulkChunkSize {
+		end := min(start+bulkChunkSize, len(items))
+		results = append(results, services.BulkAddNewsEntries(c.Request.Context(), items[start:end])...)
+	}
+
+	utils.SuccessResponse(c, dto.BulkIngestResponse{
+		Results: results,
+		Summary: services.SummarizeBulkResults(results),
+	})
+}
+
+// parseBulkItem decodes and validates a single bulk item.
+func parseBulkItem(c *gin.Context, index int, raw []byte) dto.BulkIngestItem {
+	item := dto.BulkIngestItem{Index: index}
+
+	var req dto.AddNewsRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		item.Errors = validationMessages(err)
+		return item
+	}
+	item.Request = &req
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		item.Errors = validationMessages(err)
+	}
+	if err := checkEditorialFields(c, &req); err != nil {
+		item.Errors = append(item.Errors, errEditorialFields.Message)
+	}
+	return item
+}
+
+// streamNDJSONBulk reads the body line by line, ingesting every full chunk as
+// soon as it is read and writing one result line per item, followed by a
+// final {"summary": {...}} line. It stops once the client has gone away.
+func streamNDJSONBulk(c *gin.Context) {
+	ctx := c.Request.Context()
+	// Results are written while the body is still being read.
+	fullDuplex := http.NewResponseController(c.Writer).EnableFullDuplex() == nil
+
+	encoder := json.NewEncoder(c.Writer)
+	started := false
+	var writeErr error
+	write := func(v interface{}) {
+		if writeErr != nil {
+			return
+		}
+		if !started {
+			// The status is only committed with the first result.
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			started = true
+		}
+		if writeErr = encoder.Encode(v); writeErr != nil {
+			slog.WarnContext(ctx, "Failed to write bulk result, stopping", "error", writeErr)
+		}
+	}
+	gone := func() bool {
+		return writeErr != nil || ctx.Err() != nil
+	}
+
+	var buffered []dto.BulkItemResult
+	var all []dto.BulkItemResult
+	emit := func(results []dto.BulkItemResult) {
+		all = append(all, results...)
+		if !fullDuplex {
+			buffered = append(buffered, results...)
+			return
+		}
+		for _, result := range results {
+			write(result)
+		}
+		c.Writer.Flush()
+	}
+
+	scanner := bufio.NewScanner(c.Request.Body)
+	scanner.Buffer(make([]byte, 64*1024), maxNDJSONLineSize)
+
+	var chunk []dto.BulkIngestItem
+	index := 0
+	for !gone() && scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		chunk = append(chunk, parseBulkItem(c, index, line))
+		index++
+
+		if len(chunk) == bulkChunkSize {
+			emit(services.BulkAddNewsEntries(ctx, chunk))
+			chunk = nil
+		}
+	}
+	if gone() {
+		slog.WarnContext(ctx, "Client went away during bulk ingest", "items_read", index, "items_processed", len(all))
+		middleware.MarkIncomplete(c)
+		return
+	}
+	// Nothing was ingested or written yet, so the error can still be a 400
+	if err := scanner.Err(); err != nil && index == 0 {
+		utils.Error(c, apperror.Validation("Invalid input: "+err.Error()))
+		return
+	}
+	if len(chunk) > 0 {
+		emit(services.BulkAddNewsEntries(ctx, chunk))
+	}
+
+	for _, result := range buffered {
+		write(result)
+	}
+	if err := scanner.Err(); err != nil {
+		write(gin.H{"error": fmt.Sprintf("stopped reading body after item %d: %v", index, err)})
+	}
+	write(gin.H{"summary": services.SummarizeBulkResults(all)})
+	c.Writer.Flush()
+}
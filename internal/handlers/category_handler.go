@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /news/taxonomy
+func GetTaxonomy(c *gin.Context) {
+	utils.SuccessResponse(c, services.GetTaxonomyTree())
+}
+
+// PUT /admin/categories
+func UpsertCategory(c *gin.Context) {
+	var req dto.UpsertCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+
+	category, err := services.UpsertCategory(&req)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to save category", err))
+		return
+	}
+
+	utils.SuccessResponse(c, category)
+}
+
+// DELETE /admin/categories/:slug
+func DeleteCategory(c *gin.Context) {
+	err := services.DeleteCategory(c.Param("slug"))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to delete category", err))
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"deleted": c.Param("slug")})
+}
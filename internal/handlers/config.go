@@ -0,0 +1,11 @@
+package handlers
+
+import "news-api/internal/config"
+
+// gemini holds the smart router's Gemini settings; an empty key disables it.
+var gemini = config.Default().Gemini
+
+// Configure sets the configuration used by the handlers.
+func Configure(cfg *config.Config) {
+	gemini = cfg.Gemini
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/middleware"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var exportContentTypes = map[string]string{
+	services.ExportFormatCSV:    "text/csv; charset=utf-8",
+	services.ExportFormatNDJSON: "application/x-ndjson",
+}
+
+func getExportFormat(c *gin.Context) (string, error) {
+	format := c.DefaultQuery("format", services.ExportFormatNDJSON)
+	if _, ok := exportContentTypes[format]; !ok {
+		utils.Error(c, apperror.Validation("Invalid format. Use csv or ndjson"))
+		return "", fmt.Errorf("invalid export format")
+	}
+	return format, nil
+}
+
+func setExportHeaders(c *gin.Context, name, format string) {
+	filename := fmt.Sprintf("%s_%s.%s", name, time.Now().UTC().Format("20060102T150405Z"), format)
+	c.Header("Content-Type", exportContentTypes[format])
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(200)
+}
+
+// GET /news/export
+func ExportNews(c *gin.Context) {
+	format, err := getExportFormat(c)
+	if err != nil {
+		return
+	}
+
+	var params dto.NewsFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.Error(c, apperror.Validation("Invalid filter: "+err.Error()))
+		return
+	}
+	filter, err := services.BuildNewsFilter(params)
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid filter: "+err.Error()))
+		return
+	}
+
+	includeEmbedding, err := strconv.ParseBool(c.DefaultQuery("include_embedding", "false"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid include_embedding value"))
+		return
+	}
+	// Admins export every article; other keys only what they can list
+	if !middleware.IsAdmin(c) {
+		filter = services.ReaderFilter(filter)
+	}
+
+	// The export stops when the client disconnects.
+	ctx := c.Request.Context()
+	setExportHeaders(c, "news_articles", format)
+	count, err := services.ExportArticles(ctx, c.Writer, filter, format, includeEmbedding)
+	logExportError(ctx, "Article export", count, err)
+}
+
+// GET /news/events/export
+func ExportUserEvents(c *gin.Context) {
+	format, err := getExportFormat(c)
+	if err != nil {
+		return
+	}
+
+	var params dto.EventFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.Error(c, apperror.Validation("Invalid filter: "+err.Error()))
+		return
+	}
+	filter, err := services.BuildEventFilter(params)
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid filter: "+err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	setExportHeaders(c, "user_events", format)
+	count, err := services.ExportEvents(ctx, c.Writer, filter, format)
+	logExportError(ctx, "Event export", count, err)
+}
+
+// logExportError logs an export that ended early. Headers are already sent,
+// so the truncated body is all the client gets.
+func logExportError(ctx context.Context, export string, rows int, err error) {
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		slog.WarnContext(ctx, export+" stopped, client went away", "rows", rows)
+	default:
+		slog.ErrorContext(ctx, export+" failed", "rows", rows, "error", err)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// POST /feeds
+func CreateFeed(c *gin.Context) {
+	var req dto.AddFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+
+	feed, err := services.AddFeed(&req)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to add feed", err))
+		return
+	}
+
+	utils.SuccessResponse(c, feed)
+}
+
+// GET /feeds
+func GetFeeds(c *gin.Context) {
+	feeds, err := services.GetFeeds()
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve feeds", err))
+		return
+	}
+
+	utils.SuccessResponse(c, feeds)
+}
+
+// GET /feeds/:id
+func GetFeed(c *gin.Context) {
+	feedID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid feed id"))
+		return
+	}
+
+	feed, err := services.GetFeed(feedID)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve feed", err))
+		return
+	}
+
+	utils.SuccessResponse(c, feed)
+}
+
+// DELETE /feeds/:id
+func DeleteFeed(c *gin.Context) {
+	feedID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid feed id"))
+		return
+	}
+
+	err = services.DeleteFeed(feedID)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to delete feed", err))
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"message": "Feed deleted successfully"})
+}
+
+// POST /feeds/:id/poll
+func PollFeed(c *gin.Context) {
+	feedID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid feed id"))
+		return
+	}
+
+	feed, err := services.GetFeed(feedID)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve feed", err))
+		return
+	}
+
+	// The poll finishes even when the client goes away.
+	utils.SuccessResponse(c, services.PollFeed(context.WithoutCancel(c.Request.Context()), feed))
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"news-api/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var startedAt = time.Now()
+
+// GET /healthz
+// Liveness only: the process is up and serving. Dependencies are not checked,
+// so an outage of MongoDB or Redis does not get the process restarted.
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":         services.HealthStatusOK,
+		"uptime_seconds": int64(time.Since(startedAt).Seconds()),
+	})
+}
+
+// GET /readyz
+// Readiness: 200 while every required dependency is up (status ok or
+// degraded), 503 otherwise.
+func Readyz(c *gin.Context) {
+	report := services.CheckReadiness()
+	status := http.StatusOK
+	if report.Status == services.HealthStatusUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
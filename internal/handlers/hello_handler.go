@@ -22,7 +22,7 @@ func PostHello(c *gin.Context) {
 	// 1. Parse and validate request
 	var req dto.HelloPostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, 400, "Invalid input: "+err.Error())
+		utils.Error(c, utils.InvalidInput(err))
 		return
 	}
 
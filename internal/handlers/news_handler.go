@@ -1,52 +1,76 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"news-api/internal/apperror"
 	"news-api/internal/dto"
+	"news-api/internal/metrics"
+	"news-api/internal/middleware"
+	"news-api/internal/models"
 	"news-api/internal/services"
+	"news-api/internal/tracing"
 	"news-api/internal/utils"
-	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/generative-ai-go/genai"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/api/option"
 )
 
 func GetCategories(c *gin.Context) {
 	categories, err := services.GetAllCategories()
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to retrieve categories: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to retrieve categories", err))
 		return
 	}
 	utils.SuccessResponse(c, categories)
 }
 
-func GetSourceNames(c *gin.Context) {
-	sourceNames, err := services.GetAllSourceNames()
+func GetSources(c *gin.Context) {
+	sources, err := services.GetSourceSummaries()
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to retrieve source names: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to retrieve sources", err))
 		return
 	}
-	utils.SuccessResponse(c, sourceNames)
+	utils.SuccessResponse(c, sources)
+}
+
+// errEditorialFields refuses ingest requests that set workflow fields without
+// the admin scope; ingest keys may only submit articles to be published.
+var errEditorialFields = apperror.New(apperror.KindForbidden, "status, publish_at and breaking require the admin scope")
+
+// checkEditorialFields returns errEditorialFields when req sets status,
+// publish_at or breaking and the request lacks the admin scope.
+func checkEditorialFields(c *gin.Context, req *dto.AddNewsRequest) error {
+	if (req.Status != "" || req.PublishAt != "" || req.Breaking) && !middleware.IsAdmin(c) {
+		return errEditorialFields
+	}
+	return nil
 }
 
 func CreateNewsEntry(c *gin.Context) {
 	// 1. Parse and validate request
 	var req dto.AddNewsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, 400, "Invalid input: "+err.Error())
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+	if err := checkEditorialFields(c, &req); err != nil {
+		utils.Error(c, err)
 		return
 	}
 
 	// 2. Call service layer
-	article, err := services.AddNewsEntry(&req)
+	article, err := services.AddNewsEntry(c.Request.Context(), &req)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to add news entry: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to add news entry", err))
 		return
 	}
 
@@ -57,18 +81,22 @@ func CreateNewsEntryList(c *gin.Context) {
 	// 1. Parse and validate request
 	var req []dto.AddNewsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, 400, "Invalid input: "+err.Error())
+		utils.Error(c, utils.InvalidInput(err))
 		return
 	}
 
 	// 2. Call service layer
 	var newsPointers []*dto.AddNewsRequest
 	for i := range req {
+		if err := checkEditorialFields(c, &req[i]); err != nil {
+			utils.Error(c, err)
+			return
+		}
 		newsPointers = append(newsPointers, &req[i])
 	}
-	article, err := services.AddNewsEntryList(newsPointers)
+	article, err := services.AddNewsEntryList(c.Request.Context(), newsPointers)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to add news entry: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to add news entry", err))
 		return
 	}
 
@@ -82,22 +110,117 @@ func getPaginationParams(c *gin.Context) (page, pageSize int64, err error) {
 
 	page, err = strconv.ParseInt(pageStr, 10, 64)
 	if err != nil || page <= 0 {
-		utils.ErrorResponse(c, 400, "Invalid page number")
+		utils.Error(c, apperror.Validation("Invalid page number"))
 		return 0, 0, fmt.Errorf("invalid page number")
 	}
 
 	pageSize, err = strconv.ParseInt(pageSizeStr, 10, 64)
 	if err != nil || pageSize <= 0 {
-		utils.ErrorResponse(c, 400, "Invalid page size")
+		utils.Error(c, apperror.Validation("Invalid page size"))
 		return 0, 0, fmt.Errorf("invalid page size")
 	}
 	return page, pageSize, nil
 }
 
+// listingData returns the data for a listing response: the articles alone, or
+// the articles with facet counts under the same filter when facets=true.
+// On error the response has been written and ok is false.
+func listingData(c *gin.Context, filter primitive.M, opts services.NewsQueryOptions, articles []dto.NewsArticleResponse) (data interface{}, ok bool) {
+	facets, ok := getRequestedFacets(c, filter, opts)
+	if !ok {
+		return nil, false
+	}
+	if facets == nil {
+		return articles, true
+	}
+	return gin.H{"articles": articles, "facets": facets}, true
+}
+
+// getRequestedFacets computes facets when the request asks for them with
+// facets=true, returning nil otherwise.
+func getRequestedFacets(c *gin.Context, filter primitive.M, opts services.NewsQueryOptions) (*dto.NewsFacets, bool) {
+	wantFacets, err := strconv.ParseBool(c.DefaultQuery("facets", "false"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid facets value"))
+		return nil, false
+	}
+	if !wantFacets {
+		return nil, true
+	}
+
+	interval := c.DefaultQuery("interval", "day")
+	if !services.IsValidFacetInterval(interval) {
+		utils.Error(c, apperror.Validation("Invalid interval. Use day, week or month"))
+		return nil, false
+	}
+
+	facets, err := services.GetNewsFacets(filter, interval, opts)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to compute facets", err))
+		return nil, false
+	}
+	return facets, true
+}
+
+// GET /news/facets
+func GetNewsFacets(c *gin.Context) {
+	var params dto.NewsFilterParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.Error(c, apperror.Validation("Invalid filter: "+err.Error()))
+		return
+	}
+	filter, err := services.BuildNewsFilter(params)
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid filter: "+err.Error()))
+		return
+	}
+
+	opts, err := getNewsQueryOptions(c)
+	if err != nil {
+		return
+	}
+
+	interval := c.DefaultQuery("interval", "day")
+	if !services.IsValidFacetInterval(interval) {
+		utils.Error(c, apperror.Validation("Invalid interval. Use day, week or month"))
+		return
+	}
+
+	facets, err := services.GetNewsFacets(filter, interval, opts)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to compute facets", err))
+		return
+	}
+
+	utils.SuccessResponse(c, facets)
+}
+
+func getNewsQueryOptions(c *gin.Context) (services.NewsQueryOptions, error) {
+	var opts services.NewsQueryOptions
+
+	if collapseStr := c.Query("collapse"); collapseStr != "" {
+		collapse, err := strconv.ParseBool(collapseStr)
+		if err != nil {
+			utils.Error(c, apperror.Validation("Invalid collapse value"))
+			return opts, fmt.Errorf("invalid collapse value")
+		}
+		opts.Collapse = collapse
+	}
+	if archivedStr := c.Query("include_archived"); archivedStr != "" {
+		includeArchived, err := strconv.ParseBool(archivedStr)
+		if err != nil {
+			utils.Error(c, apperror.Validation("Invalid include_archived value"))
+			return opts, fmt.Errorf("invalid include_archived value")
+		}
+		opts.IncludeArchived = includeArchived
+	}
+	return opts, nil
+}
+
 func GetCategoryNews(c *gin.Context) {
 	category := c.Param("category")
 	if category == "" {
-		utils.ErrorResponse(c, 400, "Category parameter is missing")
+		utils.Error(c, apperror.Validation("Category parameter is missing"))
 		return
 	}
 
@@ -106,25 +229,49 @@ func GetCategoryNews(c *gin.Context) {
 		return // Error response already handled by getPaginationParams
 	}
 
-	articles, err := services.FindNews(primitive.M{"category": category}, page, pageSize)
+	opts, err := getNewsQueryOptions(c)
+	if err != nil {
+		return
+	}
+
+	// include_descendants=true also matches subcategories, e.g. sports -> cricket, ipl
+	includeDescendants, err := strconv.ParseBool(c.DefaultQuery("include_descendants", "false"))
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to retrieve news by category: "+err.Error())
+		utils.Error(c, apperror.Validation("Invalid include_descendants value"))
 		return
 	}
+	filter := services.CategoryFilter(category, includeDescendants)
 
-	utils.SuccessResponse(c, articles)
+	// Editor pins for this category (and region) come first on page 1
+	pinned, err := services.GetPinnedArticles(models.PinFeedCategory, category, c.Query("region"))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve pinned news", err))
+		return
+	}
+
+	articles, err := services.FindNewsWithPins(c.Request.Context(), filter, pinned, page, pageSize, opts)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve news by category", err))
+		return
+	}
+
+	data, ok := listingData(c, filter, opts, articles)
+	if !ok {
+		return
+	}
+	utils.ArticlesResponse(c, "Category: "+category, articles, data)
 }
 
 func GetNewsByScore(c *gin.Context) {
 	scoreStr := c.Param("score")
 	if scoreStr == "" {
-		utils.ErrorResponse(c, 400, "Score parameter is missing")
+		utils.Error(c, apperror.Validation("Score parameter is missing"))
 		return
 	}
 
 	score, err := strconv.ParseFloat(scoreStr, 64)
 	if err != nil {
-		utils.ErrorResponse(c, 400, "Invalid score value")
+		utils.Error(c, apperror.Validation("Invalid score value"))
 		return
 	}
 
@@ -133,20 +280,29 @@ func GetNewsByScore(c *gin.Context) {
 		return
 	}
 
+	opts, err := getNewsQueryOptions(c)
+	if err != nil {
+		return
+	}
+
 	filter := primitive.M{"relevance_score": primitive.M{"$gte": score}}
-	articles, err := services.FindNews(filter, page, pageSize)
+	articles, err := services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to retrieve news by score: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to retrieve news by score", err))
 		return
 	}
 
-	utils.SuccessResponse(c, articles)
+	data, ok := listingData(c, filter, opts, articles)
+	if !ok {
+		return
+	}
+	utils.ArticlesResponse(c, "Score: "+scoreStr, articles, data)
 }
 
 func SearchNews(c *gin.Context) {
 	query := c.Query("q")
 	if query == "" {
-		utils.ErrorResponse(c, 400, "Search query parameter 'q' is missing")
+		utils.Error(c, apperror.Validation("Search query parameter 'q' is missing"))
 		return
 	}
 
@@ -155,25 +311,34 @@ func SearchNews(c *gin.Context) {
 		return
 	}
 
+	opts, err := getNewsQueryOptions(c)
+	if err != nil {
+		return
+	}
+
 	filter := primitive.M{
 		"$or": []primitive.M{
 			{"title": primitive.Regex{Pattern: query, Options: "i"}},
 			{"description": primitive.Regex{Pattern: query, Options: "i"}},
 		},
 	}
-	articles, err := services.FindNews(filter, page, pageSize)
+	articles, err := services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to search news: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to search news", err))
 		return
 	}
 
-	utils.SuccessResponse(c, articles)
+	data, ok := listingData(c, filter, opts, articles)
+	if !ok {
+		return
+	}
+	utils.ArticlesResponse(c, "Search: "+query, articles, data)
 }
 
 func GetNewsBySource(c *gin.Context) {
 	source := c.Param("source")
 	if source == "" {
-		utils.ErrorResponse(c, 400, "Source parameter is missing")
+		utils.Error(c, apperror.Validation("Source parameter is missing"))
 		return
 	}
 
@@ -182,14 +347,23 @@ func GetNewsBySource(c *gin.Context) {
 		return
 	}
 
-	filter := primitive.M{"source_name": source}
-	articles, err := services.FindNews(filter, page, pageSize)
+	opts, err := getNewsQueryOptions(c)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to retrieve news by source: "+err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, articles)
+	filter := services.SourceFilter(source)
+	articles, err := services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve news by source", err))
+		return
+	}
+
+	data, ok := listingData(c, filter, opts, articles)
+	if !ok {
+		return
+	}
+	utils.ArticlesResponse(c, "Source: "+source, articles, data)
 }
 
 func GetNewsNearby(c *gin.Context) {
@@ -198,23 +372,23 @@ func GetNewsNearby(c *gin.Context) {
 	radiusStr := c.Query("radius")
 
 	if latitudeStr == "" || longitudeStr == "" || radiusStr == "" {
-		utils.ErrorResponse(c, 400, "Latitude, Longitude, or Radius parameter is missing")
+		utils.Error(c, apperror.Validation("Latitude, Longitude, or Radius parameter is missing"))
 		return
 	}
 
 	latitude, err := strconv.ParseFloat(latitudeStr, 64)
 	if err != nil {
-		utils.ErrorResponse(c, 400, "Invalid latitude value")
+		utils.Error(c, apperror.Validation("Invalid latitude value"))
 		return
 	}
 	longitude, err := strconv.ParseFloat(longitudeStr, 64)
 	if err != nil {
-		utils.ErrorResponse(c, 400, "Invalid longitude value")
+		utils.Error(c, apperror.Validation("Invalid longitude value"))
 		return
 	}
 	radius, err := strconv.ParseFloat(radiusStr, 64)
 	if err != nil || radius <= 0 {
-		utils.ErrorResponse(c, 400, "Invalid radius value")
+		utils.Error(c, apperror.Validation("Invalid radius value"))
 		return
 	}
 
@@ -223,6 +397,11 @@ func GetNewsNearby(c *gin.Context) {
 		return
 	}
 
+	opts, err := getNewsQueryOptions(c)
+	if err != nil {
+		return
+	}
+
 	// MongoDB geospatial query for articles within a circle
 	filter := primitive.M{
 		"location": primitive.M{
@@ -235,13 +414,17 @@ func GetNewsNearby(c *gin.Context) {
 		},
 	}
 
-	articles, err := services.FindNews(filter, page, pageSize)
+	articles, err := services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to retrieve nearby news: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to retrieve nearby news", err))
 		return
 	}
 
-	utils.SuccessResponse(c, articles)
+	data, ok := listingData(c, filter, opts, articles)
+	if !ok {
+		return
+	}
+	utils.ArticlesResponse(c, "Nearby news", articles, data)
 }
 
 type GeminiResponse struct {
@@ -252,23 +435,24 @@ type GeminiResponse struct {
 func SmartNewsRouter(c *gin.Context) {
 	userQuery := c.Query("q")
 	if userQuery == "" {
-		utils.ErrorResponse(c, 400, "Query parameter 'q' is missing")
+		utils.Error(c, apperror.Validation("Query parameter 'q' is missing"))
+		return
+	}
+
+	if gemini.APIKey == "" {
+		utils.Error(c, apperror.New(apperror.KindUpstreamUnavailable, "Smart router is not configured: GEMINI_API_KEY is not set"))
 		return
 	}
 
-	ctx := context.Background()
-	client, err := genai.NewClient(ctx, option.WithAPIKey(os.Getenv("GEMINI_API_KEY")))
+	ctx := c.Request.Context()
+	client, err := genai.NewClient(ctx, option.WithAPIKey(gemini.APIKey))
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to create Gemini client: "+err.Error())
+		utils.Error(c, apperror.Upstream("Failed to create Gemini client", err))
 		return
 	}
 	defer client.Close()
 
-	emeddings, err := services.GetEmbeddingsfromText(userQuery)
-	fmt.Println("Hello there ")
-	fmt.Println(emeddings)
-
-	model := client.GenerativeModel("gemini-2.5-flash")
+	model := client.GenerativeModel(gemini.Model)
 
 	prompt := fmt.Sprintf(`
 You are a query router for a news API.
@@ -355,14 +539,19 @@ and these are our categories [
 Return only valid JSON.
 User query: "%s"`, userQuery)
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	geminiStart := time.Now()
+	geminiCtx, span := tracing.Start(ctx, "gemini GenerateContent", trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(attribute.String("gemini.model", gemini.Model))
+	resp, err := model.GenerateContent(geminiCtx, genai.Text(prompt))
+	tracing.End(span, err)
+	metrics.ObserveExternalCall(metrics.ServiceGemini, geminiStart, err)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to get response from Gemini: "+err.Error())
+		utils.Error(c, apperror.Upstream("Failed to get response from Gemini", err))
 		return
 	}
 
 	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
-		utils.ErrorResponse(c, 500, "Gemini returned no content")
+		utils.Error(c, apperror.New(apperror.KindUpstreamUnavailable, "Gemini returned no content"))
 		return
 	}
 
@@ -382,7 +571,7 @@ User query: "%s"`, userQuery)
 
 	err = json.Unmarshal([]byte(geminiText), &geminiResponse)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to parse Gemini response: "+err.Error())
+		utils.Error(c, apperror.Upstream("Failed to parse Gemini response", err))
 		return
 	}
 
@@ -391,6 +580,11 @@ User query: "%s"`, userQuery)
 		return
 	}
 
+	opts, err := getNewsQueryOptions(c)
+	if err != nil {
+		return
+	}
+
 	var filter primitive.M
 	var articles []dto.NewsArticleResponse
 	// var err error // Declare err here to avoid redeclaration in switch cases
@@ -399,34 +593,34 @@ User query: "%s"`, userQuery)
 	case "category":
 		for _, e := range geminiResponse.Entities {
 			if e.Type == "category" {
-				filter = primitive.M{"category": e.Value}
+				filter = services.CategoryFilter(e.Value, true)
 				break
 			}
 		}
-		articles, err = services.FindNews(filter, page, pageSize)
+		articles, err = services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
 
 	case "source":
 		for _, e := range geminiResponse.Entities {
 			if e.Type == "source" {
-				filter = primitive.M{"source_name": primitive.Regex{Pattern: "^" + e.Value + "$", Options: "i"}}
+				filter = services.SourceFilter(e.Value)
 				break
 			}
 		}
-		articles, err = services.FindNews(filter, page, pageSize)
+		articles, err = services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
 
 	case "score":
 		for _, e := range geminiResponse.Entities {
 			if e.Type == "score" {
 				score, parseErr := strconv.ParseFloat(e.Value, 64)
 				if parseErr != nil {
-					utils.ErrorResponse(c, 400, "Invalid score value")
+					utils.Error(c, apperror.Validation("Invalid score value"))
 					return
 				}
 				filter = primitive.M{"relevance_score": primitive.M{"$gte": score}}
 				break
 			}
 		}
-		articles, err = services.FindNews(filter, page, pageSize)
+		articles, err = services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
 
 	case "search":
 		var orClauses []primitive.M
@@ -440,9 +634,9 @@ User query: "%s"`, userQuery)
 					primitive.M{"description": primitive.Regex{Pattern: e.Value, Options: "i"}},
 				)
 			case "source":
-				andClauses = append(andClauses, primitive.M{"source_name": e.Value})
+				andClauses = append(andClauses, services.SourceFilter(e.Value))
 			case "category":
-				andClauses = append(andClauses, primitive.M{"category": e.Value})
+				andClauses = append(andClauses, services.CategoryFilter(e.Value, true))
 			}
 		}
 
@@ -460,7 +654,7 @@ User query: "%s"`, userQuery)
 				},
 			}
 		}
-		articles, err = services.FindNews(filter, page, pageSize)
+		articles, err = services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
 
 	case "nearby":
 		latStr := c.Query("lat")
@@ -468,23 +662,23 @@ User query: "%s"`, userQuery)
 		radiusStr := c.Query("radius")
 
 		if latStr == "" || lonStr == "" || radiusStr == "" {
-			utils.ErrorResponse(c, 400, "Latitude, Longitude, or Radius query parameters are missing for nearby intent")
+			utils.Error(c, apperror.Validation("Latitude, Longitude, or Radius query parameters are missing for nearby intent"))
 			return
 		}
 
 		latitude, parseErr := strconv.ParseFloat(latStr, 64)
 		if parseErr != nil {
-			utils.ErrorResponse(c, 400, "Invalid latitude value")
+			utils.Error(c, apperror.Validation("Invalid latitude value"))
 			return
 		}
 		longitude, parseErr := strconv.ParseFloat(lonStr, 64)
 		if parseErr != nil {
-			utils.ErrorResponse(c, 400, "Invalid longitude value")
+			utils.Error(c, apperror.Validation("Invalid longitude value"))
 			return
 		}
 		radius, parseErr := strconv.ParseFloat(radiusStr, 64)
 		if parseErr != nil || radius <= 0 {
-			utils.ErrorResponse(c, 400, "Invalid radius value")
+			utils.Error(c, apperror.Validation("Invalid radius value"))
 			return
 		}
 
@@ -498,32 +692,47 @@ User query: "%s"`, userQuery)
 				},
 			},
 		}
-		articles, err = services.FindNews(filter, page, pageSize)
+		articles, err = services.FindNewsWithOptions(c.Request.Context(), filter, page, pageSize, opts)
 
 	default:
-		utils.ErrorResponse(c, 400, "Unknown intent from Gemini: "+geminiResponse.Intent)
+		utils.Error(c, apperror.New(apperror.KindUpstreamUnavailable, "Unknown intent from Gemini: "+geminiResponse.Intent))
 		return
 	}
 
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to retrieve news: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to retrieve news", err))
 		return
 	}
 
-	vectorArticles, err := SearchNewsByVectorEmbedding(c, userQuery)
-	fmt.Println(vectorArticles, "Found Vectors ")
+	vectorArticles, err := SearchNewsByVectorEmbedding(c, userQuery, opts)
 	if err == nil {
 		articles = deduplicateArticles(articles, vectorArticles)
 	}
 
-	utils.SuccessResponse(c, gin.H{
+	if opts.Collapse {
+		articles, err = services.CollapseDuplicates(articles)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Failed to collapse duplicate articles", "error", err)
+		}
+	}
+
+	data := gin.H{
 		"articles": articles,
 		"meta": gin.H{
 			"intent":         geminiResponse.Intent,
 			"entities":       geminiResponse.Entities,
 			"original_query": userQuery,
 		},
-	})
+	}
+	facets, ok := getRequestedFacets(c, filter, opts)
+	if !ok {
+		return
+	}
+	if facets != nil {
+		data["facets"] = facets
+	}
+
+	utils.ArticlesResponse(c, "Search: "+userQuery, articles, data)
 }
 
 // Add this helper function
@@ -552,16 +761,16 @@ func deduplicateArticles(articles1, articles2 []dto.NewsArticleResponse) []dto.N
 	return result
 }
 
-func SearchNewsByVectorEmbedding(c *gin.Context, userQuery string) ([]dto.NewsArticleResponse, error) {
-	embedding, err := services.GetEmbeddingsfromText(userQuery)
+func SearchNewsByVectorEmbedding(c *gin.Context, userQuery string, opts services.NewsQueryOptions) ([]dto.NewsArticleResponse, error) {
+	embedding, err := services.GetEmbeddingsfromText(c.Request.Context(), userQuery)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to get embedding for search query: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to get embedding for search query", err))
 		return nil, fmt.Errorf("failed to get embedding: %w", err)
 	}
 
-	articles, err := services.FindNewsByVectorEmbedding(embedding, 1, 10)
+	articles, err := services.FindNewsByVectorEmbedding(c.Request.Context(), embedding, 1, 10, opts)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to retrieve news by vector embedding: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to retrieve news by vector embedding", err))
 		return nil, fmt.Errorf("failed to find news by vector embedding: %w", err)
 	}
 
@@ -571,13 +780,13 @@ func SearchNewsByVectorEmbedding(c *gin.Context, userQuery string) ([]dto.NewsAr
 func GetEmbeddingsHandler(c *gin.Context) {
 	text := c.Query("text")
 	if text == "" {
-		utils.ErrorResponse(c, 400, "Text parameter is missing")
+		utils.Error(c, apperror.Validation("Text parameter is missing"))
 		return
 	}
 
-	embedding, err := services.GetEmbeddingsfromText(text)
+	embedding, err := services.GetEmbeddingsfromText(c.Request.Context(), text)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to get embedding: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to get embedding", err))
 		return
 	}
 
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GET /news/breaking
+func GetBreakingNews(c *gin.Context) {
+	page, pageSize, err := getPaginationParams(c)
+	if err != nil {
+		return
+	}
+
+	opts, err := getNewsQueryOptions(c)
+	if err != nil {
+		return
+	}
+
+	articles, err := services.FindBreakingNews(c.Request.Context(), page, pageSize, opts)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve breaking news", err))
+		return
+	}
+
+	utils.ArticlesResponse(c, "Breaking news", articles, articles)
+}
+
+// POST /admin/articles/:id/breaking
+func SetBreaking(c *gin.Context) {
+	articleID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid article id"))
+		return
+	}
+
+	var req dto.BreakingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+
+	article, err := services.SetBreaking(articleID, *req.Breaking, getActor(c))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to update article", err))
+		return
+	}
+
+	utils.SuccessResponse(c, article)
+}
+
+// POST /admin/pins
+func CreatePin(c *gin.Context) {
+	var req dto.AddPinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+
+	pin, err := services.AddPin(&req)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to add pin", err))
+		return
+	}
+
+	utils.SuccessResponse(c, pin)
+}
+
+// GET /admin/pins?active=true
+func GetPins(c *gin.Context) {
+	pins, err := services.GetPins(c.Query("active") == "true")
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve pins", err))
+		return
+	}
+
+	utils.SuccessResponse(c, pins)
+}
+
+// DELETE /admin/pins/:id
+func DeletePin(c *gin.Context) {
+	pinID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid pin id"))
+		return
+	}
+
+	err = services.DeletePin(pinID)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to delete pin", err))
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"deleted": pinID.Hex()})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PUT /admin/sources
+func UpsertSource(c *gin.Context) {
+	var req dto.UpsertSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+
+	source, err := services.UpsertSource(&req)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to save source", err))
+		return
+	}
+
+	utils.SuccessResponse(c, source)
+}
+
+// PATCH /admin/sources/:id/trust
+func UpdateSourceTrust(c *gin.Context) {
+	var req dto.UpdateSourceTrustRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+	if req.TrustWeight == nil && req.Blocked == nil {
+		utils.Error(c, apperror.Validation("Provide trust_weight and/or blocked"))
+		return
+	}
+
+	source, err := services.UpdateSourceTrust(c.Param("id"), &req)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to update source", err))
+		return
+	}
+
+	utils.SuccessResponse(c, source)
+}
+
+// DELETE /admin/sources/:id
+func DeleteSource(c *gin.Context) {
+	err := services.DeleteSource(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to delete source", err))
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"deleted": c.Param("id")})
+}
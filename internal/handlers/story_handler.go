@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GET /news/stories
+func GetStories(c *gin.Context) {
+	page, pageSize, err := getPaginationParams(c)
+	if err != nil {
+		return
+	}
+
+	filter := primitive.M{}
+	if category := c.Query("category"); category != "" {
+		filter["categories"] = category
+	}
+
+	stories, err := services.GetStories(filter, page, pageSize)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve stories", err))
+		return
+	}
+
+	utils.SuccessResponse(c, stories)
+}
+
+// GET /news/stories/:id/timeline
+func GetStoryTimeline(c *gin.Context) {
+	storyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid story id"))
+		return
+	}
+
+	timeline, err := services.GetStoryTimeline(storyID)
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve story timeline", err))
+		return
+	}
+
+	utils.SuccessResponse(c, timeline)
+}
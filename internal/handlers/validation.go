@@ -0,0 +1,16 @@
+package handlers
+
+import "news-api/internal/utils"
+
+// validationMessages is utils.FieldErrors as one readable message per field.
+func validationMessages(err error) []string {
+	var messages []string
+	for _, detail := range utils.FieldErrors(err) {
+		if detail.Field == "" {
+			messages = append(messages, detail.Message)
+		} else {
+			messages = append(messages, detail.Field+" "+detail.Message)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/middleware"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// getActor identifies who performs a mutation, for the version history. It
+// is the name of the request's API key, or the X-Actor header when
+// authentication is disabled.
+func getActor(c *gin.Context) string {
+	if key := middleware.CurrentAPIKey(c); key != nil {
+		return key.Name
+	}
+	if actor := strings.TrimSpace(c.GetHeader("X-Actor")); actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
+
+// writeArticleMutation writes the result of an article mutation.
+func writeArticleMutation(c *gin.Context, article *dto.NewsArticleResponse, err error, failure string) {
+	if err != nil {
+		utils.Error(c, apperror.Internal(failure, err))
+		return
+	}
+	utils.SuccessResponse(c, article)
+}
+
+// GET /news/:id/versions
+func GetArticleVersions(c *gin.Context) {
+	articleID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid article id"))
+		return
+	}
+
+	// Only admins see the history of articles hidden from readers
+	versions, err := services.GetArticleVersions(articleID, middleware.IsAdmin(c))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve versions", err))
+		return
+	}
+
+	utils.SuccessResponse(c, versions)
+}
+
+// PATCH /admin/articles/:id
+func UpdateArticle(c *gin.Context) {
+	articleID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid article id"))
+		return
+	}
+
+	var req dto.UpdateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+	if req.Title == nil && req.Description == nil && req.Category == nil && req.RelevanceScore == nil && req.LLMSummary == nil {
+		utils.Error(c, apperror.Validation("No fields to update"))
+		return
+	}
+
+	article, err := services.UpdateArticle(articleID, &req, getActor(c))
+	writeArticleMutation(c, article, err, "Failed to update article")
+}
+
+// POST /admin/articles/:id/resummarize
+func ResummarizeArticle(c *gin.Context) {
+	articleID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid article id"))
+		return
+	}
+
+	article, err := services.ResummarizeArticle(articleID, getActor(c))
+	writeArticleMutation(c, article, err, "Failed to re-summarize article")
+}
+
+// POST /admin/articles/:id/versions/:version/restore
+func RestoreArticleVersion(c *gin.Context) {
+	articleID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid article id"))
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		utils.Error(c, apperror.Validation("Invalid version"))
+		return
+	}
+
+	article, err := services.RestoreArticleVersion(articleID, version, getActor(c))
+	writeArticleMutation(c, article, err, "Failed to restore article version")
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// POST /admin/articles/:id/status
+func TransitionArticleStatus(c *gin.Context) {
+	articleID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.Error(c, apperror.Validation("Invalid article id"))
+		return
+	}
+
+	var req dto.ArticleStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Error(c, utils.InvalidInput(err))
+		return
+	}
+
+	article, err := services.TransitionArticleStatus(articleID, &req, getActor(c))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to update article status", err))
+		return
+	}
+
+	utils.SuccessResponse(c, article)
+}
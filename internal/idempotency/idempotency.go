@@ -0,0 +1,171 @@
+// Package idempotency stores the responses of requests sent with an
+// Idempotency-Key so that retries get the first response instead of running
+// the request again. Responses live in Redis, shared by every instance.
+package idempotency
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"news-api/internal/apperror"
+	"news-api/internal/config"
+	"news-api/internal/database"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// A reserved key is released after this long if its request never
+	// finishes, e.g. because the instance died.
+	pendingTTL = 10 * time.Minute
+	// Redis calls give up after this long.
+	redisTimeout = time.Second
+)
+
+var (
+	ErrKeyReused  = apperror.Conflict("Idempotency-Key was already used with a different request body")
+	ErrInProgress = apperror.Conflict("A request with this Idempotency-Key is still in progress")
+	// ErrResponseTooLarge is replayed for requests whose response was too
+	// large to store; the request was processed and is not run again.
+	ErrResponseTooLarge = apperror.Conflict("The request with this Idempotency-Key was already processed, but its response was too large to store")
+)
+
+var settings = config.Default().Idempotency
+
+// Configure sets how long responses are kept.
+func Configure(cfg config.IdempotencyConfig) {
+	settings = cfg
+}
+
+// Enabled reports whether Idempotency-Key headers are honoured.
+func Enabled() bool {
+	return settings.Enabled
+}
+
+// Response is a stored response. Status is 0 while the request that
+// reserved the key is still running.
+type Response struct {
+	// Fingerprint is the hash of the request body.
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+	// TooLarge marks a response that was not stored because of its size;
+	// only its status is kept.
+	TooLarge bool `json:"too_large,omitempty"`
+	// Gzipped is set on stored records whose body is compressed.
+	Gzipped bool `json:"gzipped,omitempty"`
+}
+
+// Begin reserves key for a request whose body hashes to fingerprint. It
+// returns nil if the caller should run the request and then call Complete or
+// Release, or the stored response if the key was used for the same body
+// before. A key used for a different body gives ErrKeyReused, one whose
+// request is still running ErrInProgress.
+func Begin(ctx context.Context, key, fingerprint string) (*Response, error) {
+	if database.Rdb == nil {
+		return nil, fmt.Errorf("redis client not initialized")
+	}
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	pending, err := json.Marshal(Response{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+	// The stored record can expire between SETNX and GET, so try twice.
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := database.Rdb.SetNX(ctx, key, pending, min(pendingTTL, settings.TTL.Duration)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		data, err := database.Rdb.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency record: %w", err)
+		}
+		var stored Response
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return nil, fmt.Errorf("failed to decode idempotency record: %w", err)
+		}
+		if stored.Gzipped {
+			if stored.Body, err = gunzip(stored.Body); err != nil {
+				return nil, fmt.Errorf("failed to decompress idempotency record: %w", err)
+			}
+			stored.Gzipped = false
+		}
+		switch {
+		case stored.Fingerprint != fingerprint:
+			return nil, ErrKeyReused
+		case stored.Status == 0:
+			return nil, ErrInProgress
+		}
+		return &stored, nil
+	}
+	return nil, fmt.Errorf("failed to reserve idempotency key: record keeps expiring")
+}
+
+// Complete stores the response of a request that reserved key, to be
+// replayed for TTL. The body is stored compressed.
+func Complete(ctx context.Context, key string, response Response) error {
+	if len(response.Body) > 0 {
+		compressed, err := gzipBytes(response.Body)
+		if err != nil {
+			return fmt.Errorf("failed to compress idempotency record: %w", err)
+		}
+		response.Body, response.Gzipped = compressed, true
+	}
+	data, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), redisTimeout)
+	defer cancel()
+	if err := database.Rdb.Set(ctx, key, data, settings.TTL.Duration).Err(); err != nil {
+		return fmt.Errorf("failed to store idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release frees key so the request can be retried, e.g. after a server
+// error.
+func Release(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), redisTimeout)
+	defer cancel()
+	if err := database.Rdb.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,203 @@
+// Package metrics defines the Prometheus metrics exposed on /metrics and the
+// hooks that record them for HTTP requests, MongoDB, Redis, outbound calls
+// and cron jobs.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const (
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+)
+
+// Names of the external services recorded by ObserveExternalCall.
+const (
+	ServiceEmbedding     = "embedding"
+	ServiceSummarization = "summarization"
+	ServiceGemini        = "gemini"
+)
+
+// Results recorded by ObserveTrendingCache.
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+// Results recorded by ObserveIdempotency.
+const (
+	IdempotencyStored   = "stored"
+	IdempotencyReplayed = "replayed"
+	IdempotencyConflict = "conflict"
+	IdempotencyError    = "error"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route template and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	mongoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongodb_command_duration_seconds",
+		Help:    "MongoDB command latency by command name and outcome.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"command", "outcome"})
+
+	redisDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "redis_command_duration_seconds",
+		Help:    "Redis command latency by command name and outcome.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"command", "outcome"})
+
+	externalDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "external_call_duration_seconds",
+		Help:    "Latency of calls to the embedding, summarization and Gemini services.",
+		Buckets: []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"service", "outcome"})
+
+	externalErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "external_call_errors_total",
+		Help: "Failed calls to the embedding, summarization and Gemini services.",
+	}, []string{"service"})
+
+	trendingCache = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "trending_cache_requests_total",
+		Help: "Trending cache lookups by result (hit, miss or error).",
+	}, []string{"result"})
+
+	rateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limited_requests_total",
+		Help: "Requests refused by the rate limiter by budget and client kind (ip or key).",
+	}, []string{"budget", "client"})
+
+	idempotentRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "idempotent_requests_total",
+		Help: "Requests with an Idempotency-Key by result (stored, replayed, conflict or error).",
+	}, []string{"result"})
+
+	cronDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cron_job_duration_seconds",
+		Help:    "Cron job run time by job and outcome.",
+		Buckets: []float64{.1, .5, 1, 5, 10, 30, 60, 300, 600, 1800},
+	}, []string{"job", "outcome"})
+
+	cronLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cron_job_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful run of each cron job.",
+	}, []string{"job"})
+)
+
+func outcome(err error) string {
+	if err != nil {
+		return outcomeError
+	}
+	return outcomeSuccess
+}
+
+// ObserveHTTPRequest records a finished HTTP request. route is the route
+// template, not the raw path, so IDs do not create new series.
+func ObserveHTTPRequest(method, route, status string, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, status).Inc()
+	httpDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+// ObserveExternalCall records a call to an external service started at start.
+func ObserveExternalCall(service string, start time.Time, err error) {
+	externalDuration.WithLabelValues(service, outcome(err)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		externalErrors.WithLabelValues(service).Inc()
+	}
+}
+
+// ObserveTrendingCache records the result of a trending cache lookup.
+func ObserveTrendingCache(result string) {
+	trendingCache.WithLabelValues(result).Inc()
+}
+
+// ObserveRateLimited records a request refused by the rate limiter.
+func ObserveRateLimited(budget, client string) {
+	rateLimited.WithLabelValues(budget, client).Inc()
+}
+
+// ObserveIdempotency records how a request with an Idempotency-Key was
+// handled.
+func ObserveIdempotency(result string) {
+	idempotentRequests.WithLabelValues(result).Inc()
+}
+
+// TrackCronJob wraps a cron job so its run time and last success are recorded.
+func TrackCronJob(name string, job func() error) func() {
+	return func() {
+		start := time.Now()
+		err := job()
+		cronDuration.WithLabelValues(name, outcome(err)).Observe(time.Since(start).Seconds())
+		if err == nil {
+			cronLastSuccess.WithLabelValues(name).SetToCurrentTime()
+		}
+	}
+}
+
+// MongoMonitor records the latency of every MongoDB command.
+func MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			mongoDuration.WithLabelValues(e.CommandName, outcomeSuccess).Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			mongoDuration.WithLabelValues(e.CommandName, outcomeError).Observe(e.Duration.Seconds())
+		},
+	}
+}
+
+// RedisHook records the latency of every Redis command. A missing key
+// (redis.Nil) is a successful lookup.
+type RedisHook struct{}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeRedis(cmd.Name(), start, err)
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeRedis("pipeline", start, err)
+		return err
+	}
+}
+
+func observeRedis(command string, start time.Time, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	redisDuration.WithLabelValues(strings.ToLower(command), outcome(err)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"news-api/internal/apperror"
+	"news-api/internal/models"
+	"news-api/internal/services"
+	"news-api/internal/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyContextKey = "api_key"
+)
+
+// Authenticate requires a valid API key, sent as "Authorization: Bearer
+// <key>" or in X-API-Key, and stores it for RequireScope and handlers. It
+// lets every request through when authentication is disabled.
+func Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !services.AuthEnabled() {
+			c.Next()
+			return
+		}
+
+		secret := apiKeyFromRequest(c)
+		if secret == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			utils.Error(c, apperror.New(apperror.KindUnauthorized, "API key required"))
+			c.Abort()
+			return
+		}
+
+		key, err := services.AuthenticateAPIKey(c.Request.Context(), secret)
+		if err == services.ErrInvalidAPIKey {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			utils.Error(c, apperror.New(apperror.KindUnauthorized, "Invalid API key"))
+			c.Abort()
+			return
+		}
+		if err != nil {
+			utils.Error(c, apperror.Upstream("Failed to verify API key", err))
+			c.Abort()
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests whose API key lacks scope. Admin keys have
+// every scope.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !services.AuthEnabled() {
+			c.Next()
+			return
+		}
+		if key := CurrentAPIKey(c); key == nil || !key.HasScope(scope) {
+			utils.Error(c, apperror.New(apperror.KindForbidden, "API key lacks the "+scope+" scope"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether the request has the admin scope, as every request
+// has when authentication is disabled.
+func IsAdmin(c *gin.Context) bool {
+	if !services.AuthEnabled() {
+		return true
+	}
+	key := CurrentAPIKey(c)
+	return key != nil && key.HasScope(models.ScopeAdmin)
+}
+
+// CurrentAPIKey returns the key the request was authenticated with, or nil.
+func CurrentAPIKey(c *gin.Context) *models.APIKey {
+	key, _ := c.Get(apiKeyContextKey)
+	apiKey, _ := key.(*models.APIKey)
+	return apiKey
+}
+
+func apiKeyFromRequest(c *gin.Context) string {
+	if scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(c.GetHeader(APIKeyHeader))
+}
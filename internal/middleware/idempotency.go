@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"news-api/internal/apperror"
+	"news-api/internal/idempotency"
+	"news-api/internal/metrics"
+	"news-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is "true" on responses replayed from a
+	// previous request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// Request bodies sent with a key are read in full to fingerprint them
+	// before the handler runs, so they are limited in size.
+	MaxIdempotentBodySize = 32 << 20
+	// MaxIdempotentBulkBodySize limits bodies of the bulk route, which
+	// otherwise streams them instead of holding them in memory.
+	MaxIdempotentBulkBodySize = 8 << 20
+
+	maxIdempotencyKeyLength = 255
+	// Only the status of larger responses is stored; retries get
+	// idempotency.ErrResponseTooLarge instead of the response.
+	maxStoredResponseSize = 8 << 20
+
+	incompleteKey = "idempotency_incomplete"
+)
+
+// MarkIncomplete tells Idempotency that the handler stopped before finishing
+// the request, e.g. a streamed bulk ingest whose client went away, so its
+// response is not stored and a retry with the same key runs again.
+func MarkIncomplete(c *gin.Context) {
+	c.Set(incompleteKey, true)
+}
+
+// Idempotency replays the stored response when a request repeats the
+// Idempotency-Key of an earlier one from the same client to the same route,
+// instead of running it again. Keys are scoped by API key ID, or by IP when
+// authentication is disabled. Reusing a key with a different body, or while
+// its first request is still running, is a conflict. Server errors and
+// responses of handlers that called MarkIncomplete are not stored, so the
+// request can be retried with the same key; anything else is stored even
+// when the client has gone away, as the handler's writes happened. Bodies
+// sent with a key may be at most maxBodySize bytes. Requests without the
+// header, and all requests while Redis is unavailable, are handled normally.
+// Streamed responses are still streamed to the first caller and replayed
+// whole.
+func Idempotency(maxBodySize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(IdempotencyKeyHeader)
+		if !idempotency.Enabled() || header == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(header) {
+			utils.Error(c, apperror.Validation(fmt.Sprintf("%s must be 1 to %d printable characters", IdempotencyKeyHeader, maxIdempotencyKeyLength)))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxBodySize)+1))
+		if err != nil {
+			utils.Error(c, apperror.Validation("Failed to read request body"))
+			c.Abort()
+			return
+		}
+		if len(body) > maxBodySize {
+			utils.Error(c, apperror.Validation(fmt.Sprintf("Request bodies sent with %s must be at most %d MiB", IdempotencyKeyHeader, maxBodySize>>20)))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		key := idempotencyRecordKey(c, header)
+		fingerprint := hash(body)
+		stored, err := idempotency.Begin(ctx, key, fingerprint)
+		if err == idempotency.ErrKeyReused || err == idempotency.ErrInProgress {
+			metrics.ObserveIdempotency(metrics.IdempotencyConflict)
+			utils.Error(c, err)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			metrics.ObserveIdempotency(metrics.IdempotencyError)
+			slog.WarnContext(ctx, "Idempotency-Key ignored", "error", err)
+			c.Next()
+			return
+		}
+		if stored != nil {
+			metrics.ObserveIdempotency(metrics.IdempotencyReplayed)
+			c.Header(IdempotentReplayedHeader, "true")
+			if stored.TooLarge {
+				utils.Error(c, idempotency.ErrResponseTooLarge)
+			} else {
+				c.Data(stored.Status, stored.ContentType, stored.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		// The record is kept up to date after the client has gone away.
+		storeCtx := context.WithoutCancel(ctx)
+		completed := false
+		// A panicking handler must not leave the key reserved.
+		defer func() {
+			if completed {
+				return
+			}
+			if err := idempotency.Release(storeCtx, key); err != nil {
+				slog.WarnContext(ctx, "Failed to release Idempotency-Key", "error", err)
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError || c.GetBool(incompleteKey) {
+			return
+		}
+		response := idempotency.Response{Fingerprint: fingerprint, Status: status}
+		if writer.overflow {
+			response.TooLarge = true
+		} else {
+			response.ContentType = writer.Header().Get("Content-Type")
+			response.Body = writer.body.Bytes()
+		}
+		err = idempotency.Complete(storeCtx, key, response)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to store response for Idempotency-Key", "error", err)
+			return
+		}
+		completed = true
+		metrics.ObserveIdempotency(metrics.IdempotencyStored)
+	}
+}
+
+// idempotencyRecordKey is the Redis key of a request's record. The header is
+// hashed so its length and characters do not matter.
+func idempotencyRecordKey(c *gin.Context, header string) string {
+	client := "ip:" + c.ClientIP()
+	if key := CurrentAPIKey(c); key != nil {
+		client = "key:" + key.ClientID()
+	}
+	return fmt.Sprintf("idempotency:%s:%s:%s:%s", client, c.Request.Method, c.Request.URL.Path, hash([]byte(header)))
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for _, r := range key {
+		if r < ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordingWriter keeps a copy of the response body for storing.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.record(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// enable full duplex for streamed bulk ingests.
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *recordingWriter) record(data []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(data) > maxStoredResponseSize {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
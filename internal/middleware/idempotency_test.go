@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"news-api/internal/config"
+	"news-api/internal/database"
+	"news-api/internal/idempotency"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// useRedis points the idempotency store at an in-memory Redis.
+func useRedis(t *testing.T) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := database.Rdb
+	database.Rdb = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		database.Rdb.Close()
+		database.Rdb = client
+	})
+
+	idempotency.Configure(config.IdempotencyConfig{Enabled: true, TTL: config.Duration{Duration: time.Hour}})
+	t.Cleanup(func() { idempotency.Configure(config.Default().Idempotency) })
+}
+
+func TestIdempotencyClientGoneAfterWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		// incomplete makes the handler report that it stopped early.
+		incomplete   bool
+		wantWrites   int
+		wantReplayed string
+	}{
+		{"completed request is replayed", false, 1, "true"},
+		{"incomplete request runs again", true, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useRedis(t)
+
+			writes := 0
+			router := gin.New()
+			router.POST("/news", Idempotency(MaxIdempotentBodySize), func(c *gin.Context) {
+				writes++
+				// The client disconnects once the write is done.
+				c.Request.Context().Value(cancelKey{}).(context.CancelFunc)()
+				if tt.incomplete {
+					MarkIncomplete(c)
+					return
+				}
+				c.JSON(http.StatusOK, gin.H{"writes": writes})
+			})
+
+			send := func(ctx context.Context) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodPost, "/news", strings.NewReader(`{"title":"a"}`)).WithContext(ctx)
+				req.Header.Set(IdempotencyKeyHeader, "key-1")
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, req)
+				return recorder
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			send(context.WithValue(ctx, cancelKey{}, cancel))
+			if ctx.Err() == nil {
+				t.Fatal("first request was not cancelled")
+			}
+
+			retry := send(context.WithValue(context.Background(), cancelKey{}, context.CancelFunc(func() {})))
+			if writes != tt.wantWrites {
+				t.Errorf("writes = %d, want %d", writes, tt.wantWrites)
+			}
+			if got := retry.Header().Get(IdempotentReplayedHeader); got != tt.wantReplayed {
+				t.Errorf("%s = %q, want %q", IdempotentReplayedHeader, got, tt.wantReplayed)
+			}
+			if !tt.incomplete && retry.Body.String() != `{"writes":1}` {
+				t.Errorf("replayed body = %s, want the first response", retry.Body)
+			}
+		})
+	}
+}
+
+// cancelKey holds the function that cancels a test request's context.
+type cancelKey struct{}
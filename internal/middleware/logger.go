@@ -1,12 +1,14 @@
 package middleware
 
 import (
-	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Logger writes one structured log line per request. Server errors are
+// logged at error level and client errors at warn level.
 func Logger() gin.HandlerFunc {
 	return func(req *gin.Context) {
 		start := time.Now()
@@ -15,13 +17,29 @@ func Logger() gin.HandlerFunc {
 		req.Next()
 
 		// Log after request
-		duration := time.Since(start)
-		fmt.Printf("[%s] %s %s - %v - %d\n",
-			start.Format("2006-01-02 15:04:05"),
-			req.Request.Method,
-			req.Request.URL.Path,
-			duration,
-			req.Writer.Status(),
-		)
+		status := req.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", req.Request.Method),
+			slog.String("route", req.FullPath()),
+			slog.String("path", req.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", req.ClientIP()),
+			slog.Int("bytes", req.Writer.Size()),
+		}
+		if key := CurrentAPIKey(req); key != nil {
+			attrs = append(attrs, slog.String("api_key", key.Name))
+		}
+		if len(req.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", req.Errors.String()))
+		}
+		slog.LogAttrs(req.Request.Context(), level, "request", attrs...)
 	}
 }
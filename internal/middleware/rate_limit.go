@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"math"
+	"news-api/internal/apperror"
+	"news-api/internal/metrics"
+	"news-api/internal/ratelimit"
+	"news-api/internal/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ipDecisionKey prefixes the context keys under which RateLimitIP stores its
+// decision per budget, so RateLimit does not count the IP twice.
+const ipDecisionKey = "ratelimit_ip:"
+
+// RateLimitIP counts each request against budget for the client's IP only.
+// It runs before authentication, so clients sending invalid keys are limited
+// before every attempt costs a key lookup. Refused requests get 429 with
+// Retry-After.
+func RateLimitIP(budget string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ratelimit.Enabled() {
+			c.Next()
+			return
+		}
+
+		decision := ratelimit.Allow(c.Request.Context(), budget, ratelimit.ClientIP, c.ClientIP())
+		c.Set(ipDecisionKey+budget, decision)
+		if !decision.Allowed {
+			refuse(c, budget, ratelimit.ClientIP, decision)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimit counts each request against budget for the client's IP, unless
+// RateLimitIP already did, and, once authenticated, its API key. Keys are
+// counted by name, so a rotated key keeps its budget. The tighter of the two
+// decisions is reported in the RateLimit-* headers, and refused requests get
+// 429 with Retry-After.
+func RateLimit(budget string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ratelimit.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		client := ratelimit.ClientIP
+		var decision ratelimit.Decision
+		if stored, ok := c.Get(ipDecisionKey + budget); ok {
+			decision = stored.(ratelimit.Decision)
+		} else {
+			decision = ratelimit.Allow(ctx, budget, ratelimit.ClientIP, c.ClientIP())
+		}
+		if key := CurrentAPIKey(c); key != nil && decision.Allowed {
+			keyDecision := ratelimit.Allow(ctx, budget, ratelimit.ClientAPIKey, key.ClientID())
+			if !keyDecision.Allowed || keyDecision.Remaining < decision.Remaining {
+				client, decision = ratelimit.ClientAPIKey, keyDecision
+			}
+		}
+
+		if !decision.Allowed {
+			refuse(c, budget, client, decision)
+			return
+		}
+		setRateLimitHeaders(c, decision)
+		c.Next()
+	}
+}
+
+func setRateLimitHeaders(c *gin.Context, decision ratelimit.Decision) {
+	c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+	c.Header("RateLimit-Policy", strconv.Itoa(decision.Limit)+";w="+strconv.Itoa(seconds(decision.Window)))
+}
+
+// refuse aborts a request that exceeded the budget of client.
+func refuse(c *gin.Context, budget, client string, decision ratelimit.Decision) {
+	setRateLimitHeaders(c, decision)
+	metrics.ObserveRateLimited(budget, client)
+	reset := strconv.Itoa(seconds(decision.Reset))
+	c.Header("Retry-After", reset)
+	utils.Error(c, apperror.New(apperror.KindRateLimited, "Rate limit exceeded, retry in "+reset+"s"))
+	c.Abort()
+}
+
+// seconds rounds d up to whole seconds, at least 1.
+func seconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"log/slog"
+	"news-api/internal/apperror"
+	"news-api/internal/utils"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panic into a 500 response and logs it with its stack.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		slog.ErrorContext(c.Request.Context(), "Panic while handling request",
+			"panic", recovered,
+			"stack", string(debug.Stack()))
+		utils.Error(c, apperror.New(apperror.KindInternal, "Internal server error"))
+		c.Abort()
+	})
+}
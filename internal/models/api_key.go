@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// API key scopes. Admin grants every other scope.
+const (
+	ScopeRead   = "read"
+	ScopeIngest = "ingest"
+	ScopeEvents = "events"
+	ScopeAdmin  = "admin"
+)
+
+// APIKey authenticates a client. Only the SHA-256 hash of the secret is
+// stored; Prefix, the start of the secret, tells keys apart in listings.
+type APIKey struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Name        string              `bson:"name" json:"name"`
+	Prefix      string              `bson:"prefix" json:"prefix"`
+	KeyHash     string              `bson:"key_hash" json:"-"`
+	Scopes      []string            `bson:"scopes" json:"scopes"`
+	ExpiresAt   *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	RevokedAt   *time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	LastUsedAt  *time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	RotatedFrom *primitive.ObjectID `bson:"rotated_from,omitempty" json:"rotated_from,omitempty"`
+	CreatedBy   string              `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// HasScope reports whether the key grants scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientID identifies the key in rate limits and idempotency records. Names
+// are not unique, so it is the key's ID; the bootstrap key, which is not
+// stored and has none, is identified by its name. A rotated key gets a new ID.
+func (k *APIKey) ClientID() string {
+	if k.ID.IsZero() {
+		return k.Name
+	}
+	return k.ID.Hex()
+}
+
+// Active reports whether the key is neither revoked nor expired at now.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
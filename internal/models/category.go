@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Category is a node of the managed category taxonomy. Articles store the
+// canonical slug; aliases are alternative spellings normalized to it.
+type Category struct {
+	Slug        string    `bson:"_id" json:"slug"`
+	DisplayName string    `bson:"display_name" json:"display_name"`
+	Aliases     []string  `bson:"aliases" json:"aliases"`
+	Parent      string    `bson:"parent,omitempty" json:"parent,omitempty"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
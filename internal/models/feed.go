@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Feed is an RSS or Atom subscription polled for new articles. The category,
+// source and location fields are defaults applied to every ingested entry.
+type Feed struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	URL            string             `bson:"url" json:"url"`
+	Title          string             `bson:"title,omitempty" json:"title,omitempty"` // channel title from the last fetch
+	SourceName     string             `bson:"source_name" json:"source_name"`
+	Category       []string           `bson:"category" json:"category"`
+	RelevanceScore float64            `bson:"relevance_score" json:"relevance_score"`
+	Location       Location           `bson:"location" json:"location"`
+	Enabled        bool               `bson:"enabled" json:"enabled"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+
+	// Validators from the last successful fetch, sent back as
+	// If-None-Match / If-Modified-Since.
+	ETag         string `bson:"etag,omitempty" json:"-"`
+	LastModified string `bson:"last_modified,omitempty" json:"-"`
+
+	Health FeedHealth `bson:"health" json:"health"`
+}
+
+// FeedHealth records the outcome of recent polls of a feed.
+type FeedHealth struct {
+	Status              string     `bson:"status" json:"status"` // "pending", "ok", "degraded", "not_modified", "error"
+	LastFetchedAt       *time.Time `bson:"last_fetched_at,omitempty" json:"last_fetched_at,omitempty"`
+	LastSuccessAt       *time.Time `bson:"last_success_at,omitempty" json:"last_success_at,omitempty"`
+	LastStatusCode      int        `bson:"last_status_code,omitempty" json:"last_status_code,omitempty"`
+	LastError           string     `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	ConsecutiveFailures int        `bson:"consecutive_failures" json:"consecutive_failures"`
+	LastEntryCount      int        `bson:"last_entry_count" json:"last_entry_count"`
+	LastCreatedCount    int        `bson:"last_created_count" json:"last_created_count"`
+	TotalCreated        int        `bson:"total_created" json:"total_created"`
+}
@@ -11,10 +11,13 @@ type Article struct {
 	Title           string             `bson:"title" json:"title"`
 	Description     string             `bson:"description" json:"description"`
 	URL             string             `bson:"url" json:"url"`
+	CanonicalURL    string             `bson:"canonical_url,omitempty" json:"canonical_url,omitempty"`
 	PublicationDate time.Time          `bson:"publication_date" json:"publication_date"`
 	SourceName      string             `bson:"source_name" json:"source_name"`
+	SourceID        string             `bson:"source_id,omitempty" json:"source_id,omitempty"`
 	Category        []string           `bson:"category" json:"category"`
 	RelevanceScore  float64            `bson:"relevance_score" json:"relevance_score"`
+	RankingScore    float64            `bson:"ranking_score" json:"ranking_score"` // relevance_score × source trust weight
 
 	// Geospatial location in GeoJSON format
 	Location Location `bson:"location" json:"location"`
@@ -22,8 +25,38 @@ type Article struct {
 	// Optional enrichment fields
 	LLMSummary      string    `bson:"llm_summary" json:"llm_summary"`
 	VectorEmbedding []float64 `bson:"vector_embedding,omitempty" json:"vector_embedding,omitempty"`
+
+	// Near-duplicate tracking. SimHash is a 64-bit fingerprint of the title and
+	// description; DuplicateOf points at the representative article of a
+	// syndicated story and StoryID groups every article about the same story.
+	SimHash     int64               `bson:"simhash,omitempty" json:"-"`
+	DuplicateOf *primitive.ObjectID `bson:"duplicate_of,omitempty" json:"duplicate_of,omitempty"`
+	StoryID     *primitive.ObjectID `bson:"story_id,omitempty" json:"story_id,omitempty"`
+
+	// Editorial workflow. Only published articles that have not expired are
+	// visible on read paths; articles without a status predate the workflow
+	// and count as published.
+	Status          string     `bson:"status,omitempty" json:"status,omitempty"`
+	PublishAt       *time.Time `bson:"publish_at,omitempty" json:"publish_at,omitempty"`
+	ExpiresAt       *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	StatusUpdatedAt *time.Time `bson:"status_updated_at,omitempty" json:"status_updated_at,omitempty"`
+
+	// Breaking news, listed by GET /news/breaking newest first.
+	Breaking   bool       `bson:"breaking,omitempty" json:"breaking,omitempty"`
+	BreakingAt *time.Time `bson:"breaking_at,omitempty" json:"breaking_at,omitempty"`
+
+	// Set on articles moved to the archive collection.
+	Archived bool `bson:"archived,omitempty" json:"archived,omitempty"`
 }
 
+// Article states of the editorial workflow.
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusScheduled = "scheduled"
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
 // Location represents the GeoJSON location structure
 type Location struct {
 	Type        string    `bson:"type" json:"type"`               // always "Point"
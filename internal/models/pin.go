@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Pin feeds.
+const (
+	PinFeedCategory = "category"
+	PinFeedTrending = "trending"
+)
+
+// Pin forces an article to the top of a category or trending feed between
+// StartsAt and EndsAt. An empty Region applies to every region.
+type Pin struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ArticleID primitive.ObjectID `bson:"article_id" json:"article_id"`
+	Feed      string             `bson:"feed" json:"feed"`
+	Category  string             `bson:"category,omitempty" json:"category,omitempty"`
+	Region    string             `bson:"region,omitempty" json:"region,omitempty"`
+	Position  int                `bson:"position" json:"position"` // lower comes first
+	StartsAt  time.Time          `bson:"starts_at" json:"starts_at"`
+	EndsAt    *time.Time         `bson:"ends_at,omitempty" json:"ends_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
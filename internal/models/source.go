@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Source is an entry of the news source registry. Articles reference it by
+// ID; aliases and domains are used to resolve the source of ingested articles.
+type Source struct {
+	ID          string   `bson:"_id" json:"id"`
+	DisplayName string   `bson:"display_name" json:"display_name"`
+	Aliases     []string `bson:"aliases" json:"aliases"`
+	Domains     []string `bson:"domains" json:"domains"`
+	Country     string   `bson:"country,omitempty" json:"country,omitempty"`
+	Language    string   `bson:"language,omitempty" json:"language,omitempty"`
+	LogoURL     string   `bson:"logo_url,omitempty" json:"logo_url,omitempty"`
+
+	// TrustWeight multiplies the relevance score of the source's articles in
+	// rankings (1 when unset). Blocked sources are hidden from every listing
+	// without deleting their articles.
+	TrustWeight *float64  `bson:"trust_weight,omitempty" json:"trust_weight,omitempty"`
+	Blocked     bool      `bson:"blocked" json:"blocked"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
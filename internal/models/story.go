@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Story groups articles about one developing event. Its ID is shared with the
+// story_id field of every member article.
+type Story struct {
+	ID               primitive.ObjectID   `bson:"_id" json:"id"`
+	Title            string               `bson:"title" json:"title"`
+	Summary          string               `bson:"summary" json:"summary"`
+	Categories       []string             `bson:"categories" json:"categories"`
+	Sources          []string             `bson:"sources" json:"sources"`
+	ArticleIDs       []primitive.ObjectID `bson:"article_ids" json:"article_ids"`
+	ArticleCount     int                  `bson:"article_count" json:"article_count"`
+	FirstPublishedAt time.Time            `bson:"first_published_at" json:"first_published_at"`
+	LastPublishedAt  time.Time            `bson:"last_published_at" json:"last_published_at"`
+	UpdatedAt        time.Time            `bson:"updated_at" json:"updated_at"`
+
+	// Mean embedding of the member articles, used to attach new articles.
+	Centroid []float64 `bson:"centroid,omitempty" json:"-"`
+}
@@ -19,15 +19,19 @@ type UserEvent struct {
 
 // TrendingArticle represents an article's trending score within a cache entry.
 type TrendingArticle struct {
-	ArticleID        string  `bson:"article_id" json:"article_id"`
-	TrendingScore    float64 `bson:"trending_score" json:"trending_score"`
-	InteractionCount int     `bson:"interaction_count" json:"interaction_count"`
-	RecentActivity   int     `bson:"recent_activity" json:"recent_activity"`
-	Title            string  `bson:"title,omitempty" json:"title,omitempty"` // Enriched data
-	Description      string  `bson:"description,omitempty" json:"description,omitempty"`
-	URL              string  `bson:"url,omitempty" json:"url,omitempty"`
-	SourceName       string  `bson:"source_name,omitempty" json:"source_name,omitempty"`
-	Category         string  `bson:"category,omitempty" json:"category,omitempty"`
+	ArticleID        string    `bson:"article_id" json:"article_id"`
+	TrendingScore    float64   `bson:"trending_score" json:"trending_score"`
+	InteractionCount int       `bson:"interaction_count" json:"interaction_count"`
+	RecentActivity   int       `bson:"recent_activity" json:"recent_activity"`
+	Title            string    `bson:"title,omitempty" json:"title,omitempty"` // Enriched data
+	Description      string    `bson:"description,omitempty" json:"description,omitempty"`
+	URL              string    `bson:"url,omitempty" json:"url,omitempty"`
+	SourceName       string    `bson:"source_name,omitempty" json:"source_name,omitempty"`
+	SourceID         string    `bson:"source_id,omitempty" json:"source_id,omitempty"`
+	Category         string    `bson:"category,omitempty" json:"category,omitempty"`
+	LLMSummary       string    `bson:"llm_summary,omitempty" json:"llm_summary,omitempty"`
+	PublicationDate  time.Time `bson:"publication_date,omitempty" json:"publication_date,omitempty"`
+	Pinned           bool      `bson:"pinned,omitempty" json:"pinned,omitempty"`
 }
 
 // TrendingCache represents a cached set of trending articles for a specific geo-cluster.
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ArticleSnapshot holds the editable fields of an article.
+type ArticleSnapshot struct {
+	Title          string     `bson:"title" json:"title"`
+	Description    string     `bson:"description" json:"description"`
+	Category       []string   `bson:"category" json:"category"`
+	RelevanceScore float64    `bson:"relevance_score" json:"relevance_score"`
+	LLMSummary     string     `bson:"llm_summary" json:"llm_summary"`
+	Status         string     `bson:"status,omitempty" json:"status,omitempty"`
+	PublishAt      *time.Time `bson:"publish_at,omitempty" json:"publish_at,omitempty"`
+	ExpiresAt      *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	Breaking       bool       `bson:"breaking,omitempty" json:"breaking,omitempty"`
+}
+
+// FieldChange is the old and new value of one field in a version diff.
+type FieldChange struct {
+	Old interface{} `bson:"old" json:"old"`
+	New interface{} `bson:"new" json:"new"`
+}
+
+// ArticleVersion records one mutation of an article. Snapshot is the state
+// after the mutation; version 1 is the state before the first recorded one.
+type ArticleVersion struct {
+	ID           primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	ArticleID    primitive.ObjectID     `bson:"article_id" json:"article_id"`
+	Version      int                    `bson:"version" json:"version"`
+	Action       string                 `bson:"action" json:"action"`
+	Actor        string                 `bson:"actor" json:"actor"`
+	Timestamp    time.Time              `bson:"timestamp" json:"timestamp"`
+	Diff         map[string]FieldChange `bson:"diff,omitempty" json:"diff,omitempty"`
+	Snapshot     ArticleSnapshot        `bson:"snapshot" json:"snapshot"`
+	RestoredFrom int                    `bson:"restored_from,omitempty" json:"restored_from,omitempty"`
+}
+
+// Article version actions.
+const (
+	VersionActionInitial     = "initial"
+	VersionActionUpdate      = "update"
+	VersionActionStatus      = "status"
+	VersionActionBreaking    = "breaking"
+	VersionActionResummarize = "resummarize"
+	VersionActionRestore     = "restore"
+)
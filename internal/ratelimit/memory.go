@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// local counts requests in memory while Redis is unavailable. Each instance
+// then enforces the limits on its own.
+var local = &memoryStore{counters: make(map[string]*memoryCounter)}
+
+type memoryCounter struct {
+	start    time.Time
+	current  int64
+	previous int64
+}
+
+type memoryStore struct {
+	sync.Mutex
+	counters  map[string]*memoryCounter
+	lastSweep time.Time
+}
+
+// count applies the same check as slidingWindowScript.
+func (m *memoryStore) count(key string, start time.Time, window time.Duration, weight float64, limit int) (bool, int64, int64) {
+	m.Lock()
+	defer m.Unlock()
+	m.sweep(start, window)
+
+	counter, ok := m.counters[key]
+	if !ok {
+		counter = &memoryCounter{start: start}
+		m.counters[key] = counter
+	}
+	switch {
+	case counter.start.Equal(start):
+	case counter.start.Equal(start.Add(-window)):
+		counter.start, counter.previous, counter.current = start, counter.current, 0
+	default:
+		counter.start, counter.previous, counter.current = start, 0, 0
+	}
+
+	if float64(counter.previous)*weight+float64(counter.current+1) > float64(limit) {
+		return false, counter.current, counter.previous
+	}
+	counter.current++
+	return true, counter.current, counter.previous
+}
+
+// sweep drops counters that no longer affect any window, at most once per
+// window.
+func (m *memoryStore) sweep(start time.Time, window time.Duration) {
+	if start.Sub(m.lastSweep) < window {
+		return
+	}
+	m.lastSweep = start
+	for key, counter := range m.counters {
+		if counter.start.Before(start.Add(-window)) {
+			delete(m.counters, key)
+		}
+	}
+}
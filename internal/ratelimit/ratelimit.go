@@ -0,0 +1,169 @@
+// Package ratelimit counts requests per client in a sliding window. Counters
+// live in Redis so every instance shares them; while Redis is unavailable
+// each instance falls back to counting in memory.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"news-api/internal/config"
+	"news-api/internal/database"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Budgets. Expensive routes count against both.
+const (
+	BudgetDefault   = "default"
+	BudgetExpensive = "expensive"
+)
+
+// Kinds of client a request is counted against.
+const (
+	ClientIP     = "ip"
+	ClientAPIKey = "key"
+)
+
+var settings = config.Default().RateLimit
+
+// Configure sets the limits and the window.
+func Configure(cfg config.RateLimitConfig) {
+	settings = cfg
+}
+
+// Enabled reports whether requests are rate limited.
+func Enabled() bool {
+	return settings.Enabled
+}
+
+// Decision is the outcome of counting one request.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is when the client can make another request if it was refused,
+	// otherwise when the current window ends.
+	Reset  time.Duration
+	Window time.Duration
+}
+
+func limitFor(budget, client string) int {
+	rules := settings.PerIP
+	if client == ClientAPIKey {
+		rules = settings.PerKey
+	}
+	if budget == BudgetExpensive {
+		return rules.Expensive
+	}
+	return rules.Default
+}
+
+// Allow counts a request of the client identified by kind and id against
+// budget, unless the budget is already used up.
+func Allow(ctx context.Context, budget, kind, id string) Decision {
+	limit := limitFor(budget, kind)
+	window := settings.Window.Duration
+	now := time.Now()
+	start := now.Truncate(window)
+	// Share of the previous window that still overlaps the sliding window.
+	weight := 1 - float64(now.Sub(start))/float64(window)
+
+	key := fmt.Sprintf("ratelimit:%s:%s:%s", budget, kind, id)
+	allowed, current, previous, err := false, int64(0), int64(0), errRedisSkipped
+	if now.UnixNano() >= redisRetryAt.Load() {
+		allowed, current, previous, err = countRedis(ctx, key, start, window, weight, limit)
+		if err != nil {
+			redisRetryAt.Store(now.Add(redisRetryInterval).UnixNano())
+			if redisAvailable.Swap(false) {
+				slog.WarnContext(ctx, "Rate limiting falls back to in-memory counters", "error", err)
+			}
+		} else if !redisAvailable.Swap(true) {
+			slog.InfoContext(ctx, "Rate limiting uses Redis again")
+		}
+	}
+	if err != nil {
+		allowed, current, previous = local.count(key, start, window, weight, limit)
+	}
+
+	decision := Decision{Allowed: allowed, Limit: limit, Window: window}
+	used := float64(previous)*weight + float64(current)
+	decision.Remaining = max(0, limit-int(math.Ceil(used)))
+	if allowed {
+		decision.Reset = start.Add(window).Sub(now)
+	} else {
+		decision.Reset = retryAfter(limit, current, previous, window, start.Add(window).Sub(now))
+	}
+	return decision
+}
+
+// retryAfter is how long until one more request fits, assuming no other
+// requests arrive. untilEnd is the time left in the current window.
+func retryAfter(limit int, current, previous int64, window, untilEnd time.Duration) time.Duration {
+	free := float64(limit - 1)
+	if float64(current) <= free {
+		// Enough of the previous window has to slide out.
+		return untilEnd - time.Duration((free-float64(current))/float64(previous)*float64(window))
+	}
+	// The current window becomes the previous one and has to slide out.
+	return untilEnd + time.Duration((1-free/float64(current))*float64(window))
+}
+
+const (
+	// Redis calls give up after this long, so an unreachable Redis does not
+	// stall every request.
+	redisTimeout = 250 * time.Millisecond
+	// After a failure, Redis is tried again only after this long.
+	redisRetryInterval = 10 * time.Second
+)
+
+var errRedisSkipped = errors.New("redis skipped after a recent failure")
+
+var (
+	// redisAvailable tracks whether the last Redis call succeeded, so the
+	// switch to and from the fallback is logged once.
+	redisAvailable atomic.Bool
+	// redisRetryAt is the Unix time in nanoseconds before which Redis is
+	// not tried.
+	redisRetryAt atomic.Int64
+)
+
+func init() {
+	redisAvailable.Store(true)
+}
+
+// slidingWindowScript checks the weighted count of the current and previous
+// windows and only counts the request when it fits, so refused requests do
+// not extend a client's wait.
+var slidingWindowScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+if previous * tonumber(ARGV[1]) + current + 1 > tonumber(ARGV[2]) then
+	return {0, current, previous}
+end
+current = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {1, current, previous}
+`)
+
+func countRedis(ctx context.Context, key string, start time.Time, window time.Duration, weight float64, limit int) (bool, int64, int64, error) {
+	if database.Rdb == nil {
+		return false, 0, 0, fmt.Errorf("redis client not initialized")
+	}
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	keys := []string{
+		fmt.Sprintf("%s:%d", key, start.Unix()),
+		fmt.Sprintf("%s:%d", key, start.Add(-window).Unix()),
+	}
+	result, err := slidingWindowScript.Run(ctx, database.Rdb, keys, weight, limit, (2 * window).Milliseconds()).Int64Slice()
+	if err != nil {
+		return false, 0, 0, err
+	}
+	return result[0] == 1, result[1], result[2], nil
+}
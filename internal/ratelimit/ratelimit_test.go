@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		current  int64
+		previous int64
+		untilEnd time.Duration
+		want     time.Duration
+	}{
+		{"previous window slides out", 10, 4, 12, 30 * time.Second, 5 * time.Second},
+		{"current window full of free slots", 10, 9, 6, 30 * time.Second, 30 * time.Second},
+		{"current window slides out", 10, 10, 0, 30 * time.Second, 36 * time.Second},
+		{"current window over the limit", 10, 20, 5, 30 * time.Second, 30*time.Second + 33*time.Second},
+		{"limit of one, previous window used", 1, 0, 3, 30 * time.Second, 30 * time.Second},
+		{"limit of one, current window used", 1, 1, 0, 30 * time.Second, 90 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := retryAfter(tt.limit, tt.current, tt.previous, time.Minute, tt.untilEnd)
+			if got.Round(time.Millisecond) != tt.want {
+				t.Errorf("retryAfter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreCount(t *testing.T) {
+	const window = time.Minute
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type call struct {
+		// windows is the offset of the call's window from base.
+		windows      int
+		weight       float64
+		limit        int
+		wantAllowed  bool
+		wantCurrent  int64
+		wantPrevious int64
+	}
+	repeat := func(n int, c call) []call {
+		calls := make([]call, n)
+		for i := range calls {
+			calls[i] = c
+			calls[i].wantCurrent = int64(i + 1)
+		}
+		return calls
+	}
+	concat := func(parts ...[]call) []call {
+		var calls []call
+		for _, part := range parts {
+			calls = append(calls, part...)
+		}
+		return calls
+	}
+
+	tests := []struct {
+		name  string
+		calls []call
+	}{
+		{
+			name: "counts up to the limit",
+			calls: concat(
+				repeat(3, call{limit: 3, wantAllowed: true}),
+				[]call{{limit: 3, wantAllowed: false, wantCurrent: 3}},
+			),
+		},
+		{
+			name: "weights the previous window",
+			calls: concat(
+				repeat(4, call{windows: -1, limit: 10, wantAllowed: true}),
+				[]call{
+					{weight: 0.5, limit: 5, wantAllowed: true, wantCurrent: 1, wantPrevious: 4},
+					{weight: 0.5, limit: 5, wantAllowed: true, wantCurrent: 2, wantPrevious: 4},
+					{weight: 0.5, limit: 5, wantAllowed: true, wantCurrent: 3, wantPrevious: 4},
+					{weight: 0.5, limit: 5, wantAllowed: false, wantCurrent: 3, wantPrevious: 4},
+				},
+			),
+		},
+		{
+			name: "refused requests are not counted",
+			calls: []call{
+				{limit: 1, wantAllowed: true, wantCurrent: 1},
+				{limit: 1, wantAllowed: false, wantCurrent: 1},
+				{windows: 1, weight: 0, limit: 1, wantAllowed: true, wantCurrent: 1, wantPrevious: 1},
+			},
+		},
+		{
+			name: "forgets windows before the previous one",
+			calls: concat(
+				repeat(4, call{windows: -2, limit: 10, wantAllowed: true}),
+				[]call{
+					{weight: 1, limit: 2, wantAllowed: true, wantCurrent: 1},
+					{weight: 1, limit: 2, wantAllowed: true, wantCurrent: 2},
+					{weight: 1, limit: 2, wantAllowed: false, wantCurrent: 2},
+				},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryStore{counters: make(map[string]*memoryCounter)}
+			for i, c := range tt.calls {
+				start := base.Add(time.Duration(c.windows) * window)
+				allowed, current, previous := store.count("key", start, window, c.weight, c.limit)
+				if allowed != c.wantAllowed || current != c.wantCurrent || previous != c.wantPrevious {
+					t.Fatalf("call %d = (%v, %d, %d), want (%v, %d, %d)",
+						i, allowed, current, previous, c.wantAllowed, c.wantCurrent, c.wantPrevious)
+				}
+			}
+		})
+	}
+}
@@ -3,41 +3,102 @@ package routes
 import (
 	newsHandlers "news-api/internal/handlers"
 	"news-api/internal/middleware"
+	"news-api/internal/models"
+	"news-api/internal/ratelimit"
 	trendingHandlers "news-api/internal/trending/handlers"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func SetupRoutes(r *gin.Engine) {
 	// Apply global middleware
 	r.Use(middleware.Logger())
+	r.Use(middleware.Metrics())
 
-	// API v1 group
-	v1 := r.Group("/api/v1")
-	{
-	}
+	// API v1 group. Every route needs an API key; each group below
+	// requires the scope of what its routes do. Requests count against the
+	// default rate limit budget, per IP before authentication and per key
+	// after; expensive routes also against their own.
+	v1 := r.Group("/api/v1",
+		middleware.RateLimitIP(ratelimit.BudgetDefault),
+		middleware.Authenticate(),
+		middleware.RateLimit(ratelimit.BudgetDefault))
 
 	newsRouterV1 := v1.Group("/news")
 	{
-		newsRouterV1.POST("/", newsHandlers.CreateNewsEntry)
-		newsRouterV1.POST("/list", newsHandlers.CreateNewsEntryList)
+		ingest := newsRouterV1.Group("", middleware.RequireScope(models.ScopeIngest))
+		ingest.POST("/", middleware.Idempotency(middleware.MaxIdempotentBodySize), newsHandlers.CreateNewsEntry)
+		ingest.POST("/list", middleware.Idempotency(middleware.MaxIdempotentBodySize), newsHandlers.CreateNewsEntryList)
+		ingest.POST("/bulk", middleware.Idempotency(middleware.MaxIdempotentBulkBodySize), newsHandlers.CreateNewsEntriesBulk)
+
+		events := newsRouterV1.Group("", middleware.RequireScope(models.ScopeEvents))
+		events.POST("/events", middleware.Idempotency(middleware.MaxIdempotentBodySize), trendingHandlers.CreateUserEvent)
 
-		newsRouterV1.GET("/category/:category", newsHandlers.GetCategoryNews)
-		newsRouterV1.GET("/score/:score", newsHandlers.GetNewsByScore)
-		newsRouterV1.GET("/source/:source", newsHandlers.GetNewsBySource)
-		newsRouterV1.GET("/search", newsHandlers.SmartNewsRouter)
-		newsRouterV1.GET("/nearby", newsHandlers.GetNewsNearby)
-		newsRouterV1.GET("/categories", newsHandlers.GetCategories)
-		newsRouterV1.GET("/sources", newsHandlers.GetSourceNames)
+		read := newsRouterV1.Group("", middleware.RequireScope(models.ScopeRead))
+		read.GET("/category/:category", newsHandlers.GetCategoryNews)
+		read.GET("/score/:score", newsHandlers.GetNewsByScore)
+		read.GET("/source/:source", newsHandlers.GetNewsBySource)
+		read.GET("/search", middleware.RateLimit(ratelimit.BudgetExpensive), newsHandlers.SmartNewsRouter)
+		read.GET("/nearby", newsHandlers.GetNewsNearby)
+		read.GET("/categories", newsHandlers.GetCategories)
+		read.GET("/taxonomy", newsHandlers.GetTaxonomy)
+		read.GET("/sources", newsHandlers.GetSources)
+		read.GET("/stories", newsHandlers.GetStories)
+		read.GET("/stories/:id/timeline", newsHandlers.GetStoryTimeline)
 
-		newsRouterV1.POST("/events", trendingHandlers.CreateUserEvent)
+		read.GET("/facets", newsHandlers.GetNewsFacets)
+		read.GET("/export", newsHandlers.ExportNews)
 
-		newsRouterV1.GET("/trending", trendingHandlers.GetTrendingNews)
+		read.GET("/trending", trendingHandlers.GetTrendingNews)
+		read.GET("/breaking", newsHandlers.GetBreakingNews)
+		read.GET("/:id/versions", newsHandlers.GetArticleVersions)
 
+		// Raw events identify users, so exporting them is an admin task
+		admin := newsRouterV1.Group("", middleware.RequireScope(models.ScopeAdmin))
+		admin.GET("/events/export", newsHandlers.ExportUserEvents)
+	}
+
+	feedsRouterV1 := v1.Group("/feeds")
+	{
+		read := feedsRouterV1.Group("", middleware.RequireScope(models.ScopeRead))
+		read.GET("/", newsHandlers.GetFeeds)
+		read.GET("/:id", newsHandlers.GetFeed)
+
+		admin := feedsRouterV1.Group("", middleware.RequireScope(models.ScopeAdmin))
+		admin.POST("/", newsHandlers.CreateFeed)
+		admin.DELETE("/:id", newsHandlers.DeleteFeed)
+		admin.POST("/:id/poll", newsHandlers.PollFeed)
+	}
+
+	adminRouterV1 := v1.Group("/admin", middleware.RequireScope(models.ScopeAdmin))
+	{
+		adminRouterV1.PUT("/categories", newsHandlers.UpsertCategory)
+		adminRouterV1.DELETE("/categories/:slug", newsHandlers.DeleteCategory)
+		adminRouterV1.PUT("/sources", newsHandlers.UpsertSource)
+		adminRouterV1.PATCH("/sources/:id/trust", newsHandlers.UpdateSourceTrust)
+		adminRouterV1.DELETE("/sources/:id", newsHandlers.DeleteSource)
+		adminRouterV1.PATCH("/articles/:id", newsHandlers.UpdateArticle)
+		adminRouterV1.POST("/articles/:id/status", newsHandlers.TransitionArticleStatus)
+		adminRouterV1.POST("/articles/:id/resummarize", newsHandlers.ResummarizeArticle)
+		adminRouterV1.POST("/articles/:id/versions/:version/restore", newsHandlers.RestoreArticleVersion)
+		adminRouterV1.POST("/articles/:id/breaking", newsHandlers.SetBreaking)
+		adminRouterV1.POST("/pins", newsHandlers.CreatePin)
+		adminRouterV1.GET("/pins", newsHandlers.GetPins)
+		adminRouterV1.DELETE("/pins/:id", newsHandlers.DeletePin)
+		adminRouterV1.POST("/api-keys", newsHandlers.CreateAPIKey)
+		adminRouterV1.GET("/api-keys", newsHandlers.GetAPIKeys)
+		adminRouterV1.POST("/api-keys/:id/rotate", newsHandlers.RotateAPIKey)
+		adminRouterV1.DELETE("/api-keys/:id", newsHandlers.RevokeAPIKey)
 	}
 
 	// Health check
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "pong"})
 	})
+	r.GET("/healthz", newsHandlers.Healthz)
+	r.GET("/readyz", newsHandlers.Readyz)
+
+	// Prometheus metrics
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 }
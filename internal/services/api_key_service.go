@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"news-api/internal/apperror"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Every generated key starts with this, so leaked keys are easy to find.
+	apiKeyPrefix = "nk_"
+	// Length of the secret's start stored in APIKey.Prefix.
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+	// last_used_at is written at most this often per key.
+	apiKeyUsageInterval = time.Minute
+)
+
+var (
+	ErrAPIKeyNotFound      = apperror.NotFound("API key not found or already revoked")
+	ErrInvalidAPIKey       = apperror.New(apperror.KindUnauthorized, "Invalid API key")
+	ErrInvalidAPIKeyFields = apperror.Validation("Invalid API key request")
+)
+
+// bootstrapAPIKeyName is the actor name of requests made with the bootstrap key.
+const bootstrapAPIKeyName = "bootstrap"
+
+func apiKeyCollection() *mongo.Collection {
+	return database.GetCollection("api_keys")
+}
+
+// AuthEnabled reports whether /api/v1 routes require an API key.
+func AuthEnabled() bool {
+	return settings.Auth.Enabled
+}
+
+// EnsureAPIKeyIndexes creates the unique index used to look up keys.
+func EnsureAPIKeyIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := apiKeyCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "key_hash", Value: 1}},
+		Options: options.Index().SetName("key_hash_unique").SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create API key index: %w", err)
+	}
+	return nil
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// normalizeScopes removes duplicates and sorts scopes.
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// insertAPIKey stores a new key built from template and returns it with its
+// secret.
+func insertAPIKey(ctx context.Context, template models.APIKey) (*dto.CreatedAPIKeyResponse, error) {
+	secret, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := template
+	key.ID = primitive.NewObjectID()
+	key.Prefix = secret[:apiKeyDisplayLength]
+	key.KeyHash = hashAPIKey(secret)
+	key.CreatedAt = time.Now()
+	if _, err := apiKeyCollection().InsertOne(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to insert API key: %w", err)
+	}
+	return &dto.CreatedAPIKeyResponse{APIKey: key, Key: secret}, nil
+}
+
+// CreateAPIKey creates a key with the requested scopes.
+func CreateAPIKey(req *dto.CreateAPIKeyRequest, actor string) (*dto.CreatedAPIKeyResponse, error) {
+	key := models.APIKey{
+		Name:      strings.TrimSpace(req.Name),
+		Scopes:    normalizeScopes(req.Scopes),
+		CreatedBy: actor,
+	}
+	if key.Name == "" {
+		return nil, apperror.Errorf(ErrInvalidAPIKeyFields, "name must not be empty")
+	}
+	if req.ExpiresAt != "" {
+		expiresAt := parseTime(req.ExpiresAt)
+		if expiresAt.IsZero() || !expiresAt.After(time.Now()) {
+			return nil, apperror.Errorf(ErrInvalidAPIKeyFields, "expires_at must be a time in the future")
+		}
+		key.ExpiresAt = &expiresAt
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return insertAPIKey(ctx, key)
+}
+
+// GetAPIKeys lists all keys, including revoked and expired ones, newest first.
+func GetAPIKeys() ([]models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := apiKeyCollection().Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	keys := []models.APIKey{}
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RotateAPIKey replaces a key with a new secret that has the same name,
+// scopes and expiry, and revokes the old one.
+func RotateAPIKey(id primitive.ObjectID, actor string) (*dto.CreatedAPIKeyResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var old models.APIKey
+	err := apiKeyCollection().FindOne(ctx, bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}}).Decode(&old)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+
+	// The new key is stored first so a failure leaves the old one usable.
+	created, err := insertAPIKey(ctx, models.APIKey{
+		Name:        old.Name,
+		Scopes:      old.Scopes,
+		ExpiresAt:   old.ExpiresAt,
+		RotatedFrom: &old.ID,
+		CreatedBy:   actor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := RevokeAPIKey(id); err != nil && err != ErrAPIKeyNotFound {
+		return nil, err
+	}
+	return created, nil
+}
+
+// RevokeAPIKey revokes a key. Revoked keys are kept for auditing.
+func RevokeAPIKey(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := apiKeyCollection().UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}})
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticateAPIKey returns the active key with the given secret. The
+// configured bootstrap key authenticates as an admin key named "bootstrap".
+func AuthenticateAPIKey(ctx context.Context, secret string) (*models.APIKey, error) {
+	hash := hashAPIKey(secret)
+	if bootstrap := settings.Auth.BootstrapKey; bootstrap != "" &&
+		subtle.ConstantTimeCompare([]byte(hash), []byte(hashAPIKey(bootstrap))) == 1 {
+		return &models.APIKey{Name: bootstrapAPIKeyName, Scopes: []string{models.ScopeAdmin}}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var key models.APIKey
+	err := apiKeyCollection().FindOne(ctx, bson.M{"key_hash": hash}).Decode(&key)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, ErrInvalidAPIKey
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyUsageInterval {
+		_, err := apiKeyCollection().UpdateOne(ctx, bson.M{"_id": key.ID}, bson.M{"$set": bson.M{"last_used_at": now}})
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to record API key usage", "api_key", key.Name, "error", err)
+		}
+	}
+	return &key, nil
+}
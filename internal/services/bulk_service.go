@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"news-api/internal/utils"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Number of articles enriched (embedding + summary) concurrently in a bulk ingest.
+const bulkEnrichmentWorkers = 4
+
+// BulkAddNewsEntries ingests a batch of articles, deciding the outcome of each
+// item independently: invalid items, duplicates and enrichment failures are
+// reported without affecting the rest of the batch. Results are returned in
+// input order. The batch is not cancelled when the caller's context is; ctx
+// only carries the trace.
+func BulkAddNewsEntries(ctx context.Context, items []dto.BulkIngestItem) []dto.BulkItemResult {
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 60*10*time.Second)
+	defer cancel()
+
+	results := make([]dto.BulkItemResult, len(items))
+	canonicalURLs := make([]string, len(items))
+	var pending []int
+
+	// 1. Validation and in-batch duplicates
+	firstByURL := make(map[string]int)
+	for i, item := range items {
+		results[i] = dto.BulkItemResult{Index: item.Index}
+		if item.Request != nil {
+			results[i].URL = item.Request.URL
+		}
+		if len(item.Errors) > 0 {
+			results[i].Status = dto.BulkStatusInvalid
+			results[i].Code = apperror.KindValidation
+			results[i].Errors = item.Errors
+			continue
+		}
+
+		if err := validateIngestRequest(item.Request); err != nil {
+			failBulkItem(ctx, &results[i], dto.BulkStatusInvalid, err, ErrInvalidArticle)
+			continue
+		}
+
+		canonicalURL, err := utils.CanonicalizeURL(item.Request.URL)
+		if err != nil {
+			failBulkItem(ctx, &results[i], dto.BulkStatusInvalid, err,
+				apperror.Errorf(ErrInvalidArticle, "invalid url %q", item.Request.URL))
+			continue
+		}
+		canonicalURLs[i] = canonicalURL
+		results[i].CanonicalURL = canonicalURL
+
+		if first, found := firstByURL[canonicalURL]; found {
+			results[i].Status = dto.BulkStatusDuplicate
+			results[i].Errors = []string{fmt.Sprintf("same URL as item %d", items[first].Index)}
+			continue
+		}
+		firstByURL[canonicalURL] = i
+		pending = append(pending, i)
+	}
+
+	// 2. One lookup for articles already stored
+	existing, err := findExistingURLs(ctx, items, canonicalURLs, pending)
+	if err != nil {
+		for _, i := range pending {
+			results[i].Status = dto.BulkStatusFailed
+			results[i].Code = apperror.KindInternal
+			results[i].Errors = []string{"failed to check for existing articles"}
+		}
+		slog.ErrorContext(ctx, "Bulk ingest duplicate lookup failed", "error", err)
+		return results
+	}
+
+	var toEnrich []int
+	for _, i := range pending {
+		id, found := existing[canonicalURLs[i]]
+		if !found {
+			id, found = existing[items[i].Request.URL]
+		}
+		if found {
+			existingID := id
+			results[i].Status = dto.BulkStatusDuplicate
+			results[i].ExistingID = &existingID
+			continue
+		}
+		toEnrich = append(toEnrich, i)
+	}
+
+	// 3. Enrichment, concurrently per item
+	articles := make(map[int]models.Article)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	work := make(chan int)
+	for w := 0; w < bulkEnrichmentWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				article, err := buildArticle(ctx, items[i].Request, canonicalURLs[i])
+				mu.Lock()
+				if err != nil {
+					failBulkItem(ctx, &results[i], dto.BulkStatusEnrichmentFailed, err,
+						apperror.Upstream("enrichment failed", nil))
+				} else {
+					articles[i] = article
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, i := range toEnrich {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	// 4. Near-duplicate links, in input order so earlier items become representatives
+	var docs []interface{}
+	var docIndex []int
+	var linked []models.Article
+	for _, i := range toEnrich {
+		article, ok := articles[i]
+		if !ok {
+			continue
+		}
+		linkNearDuplicate(ctx, &article, linked)
+		linked = append(linked, article)
+		docs = append(docs, article)
+		docIndex = append(docIndex, i)
+
+		id := article.ID
+		results[i].ID = &id
+		results[i].DuplicateOf = article.DuplicateOf
+		results[i].Status = dto.BulkStatusCreated
+	}
+
+	// 5. Unordered insert; failed writes are mapped back to their items
+	if len(docs) > 0 {
+		_, err := collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+		var bulkErr mongo.BulkWriteException
+		switch {
+		case err == nil:
+		case errors.As(err, &bulkErr):
+			for _, writeErr := range bulkErr.WriteErrors {
+				i := docIndex[writeErr.Index]
+				results[i].ID = nil
+				results[i].DuplicateOf = nil
+				if writeErr.Code == 11000 {
+					results[i].Status = dto.BulkStatusDuplicate
+					results[i].Errors = []string{"article with this URL already exists"}
+				} else {
+					failBulkItem(ctx, &results[i], dto.BulkStatusFailed, writeErr,
+						apperror.Internal("failed to store article", nil))
+				}
+			}
+		default:
+			slog.ErrorContext(ctx, "Failed to insert bulk articles", "error", err)
+			for _, i := range docIndex {
+				results[i].ID = nil
+				results[i].DuplicateOf = nil
+				results[i].Status = dto.BulkStatusFailed
+				results[i].Code = apperror.KindInternal
+				results[i].Errors = []string{"failed to store article"}
+			}
+		}
+	}
+
+	return results
+}
+
+// failBulkItem reports err on result with a client-safe message: that of the
+// apperror err wraps, or fallback's when err has none or is internal. The
+// cause is logged with the request ID unless err is a validation error.
+func failBulkItem(ctx context.Context, result *dto.BulkItemResult, status string, err error, fallback *apperror.Error) {
+	safe := fallback
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) && appErr.Kind != apperror.KindInternal {
+		safe = appErr
+	}
+	if safe.Kind != apperror.KindValidation {
+		slog.WarnContext(ctx, "Bulk item failed", "index", result.Index, "status", status, "error", err)
+	}
+	result.Status = status
+	result.Code = safe.Kind
+	result.Errors = []string{safe.Message}
+}
+
+// findExistingURLs returns the IDs of stored articles keyed by both their
+// canonical and raw URL, for every pending item, using a single query.
+func findExistingURLs(ctx context.Context, items []dto.BulkIngestItem, canonicalURLs []string, pending []int) (map[string]primitive.ObjectID, error) {
+	existing := make(map[string]primitive.ObjectID)
+	if len(pending) == 0 {
+		return existing, nil
+	}
+
+	var canonical, raw []string
+	for _, i := range pending {
+		canonical = append(canonical, canonicalURLs[i])
+		raw = append(raw, items[i].Request.URL)
+	}
+
+	filter := bson.M{"$or": []bson.M{
+		{"canonical_url": bson.M{"$in": canonical}},
+		{"url": bson.M{"$in": raw}},
+	}}
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1, "url": 1, "canonical_url": 1})
+
+	for _, collection := range urlCollections() {
+		cursor, err := collection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find existing articles: %w", err)
+		}
+		var found []models.Article
+		if err = cursor.All(ctx, &found); err != nil {
+			return nil, fmt.Errorf("failed to decode existing articles: %w", err)
+		}
+		for _, article := range found {
+			existing[article.URL] = article.ID
+			if article.CanonicalURL != "" {
+				existing[article.CanonicalURL] = article.ID
+			}
+		}
+	}
+	return existing, nil
+}
+
+// SummarizeBulkResults counts results per status.
+func SummarizeBulkResults(results []dto.BulkItemResult) map[string]int {
+	summary := map[string]int{"total": len(results)}
+	for _, result := range results {
+		summary[result.Status]++
+	}
+	return summary
+}
@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"news-api/internal/apperror"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The taxonomy is cached in memory and reloaded after this long so that
+// changes made through other instances are picked up.
+const taxonomyCacheTTL = 5 * time.Minute
+
+var (
+	ErrCategoryNotFound = apperror.NotFound("Category not found")
+	ErrInvalidCategory  = apperror.Validation("Invalid category")
+)
+
+// defaultTaxonomy seeds an empty categories collection with the categories
+// found in existing articles.
+var defaultTaxonomy = []models.Category{
+	{Slug: "general", DisplayName: "General"},
+	{Slug: "miscellaneous", DisplayName: "Miscellaneous", Parent: "general"},
+	{Slug: "facts", DisplayName: "Facts", Parent: "general"},
+	{Slug: "hatke", DisplayName: "Hatke", Parent: "general"},
+	{Slug: "feel_good_stories", DisplayName: "Feel Good Stories", Parent: "general"},
+	{Slug: "explainers", DisplayName: "Explainers", Aliases: []string{"explainer"}},
+	{Slug: "politics", DisplayName: "Politics"},
+	{Slug: "national", DisplayName: "National", Aliases: []string{"india"}},
+	{Slug: "city", DisplayName: "City", Parent: "national"},
+	{Slug: "crime", DisplayName: "Crime", Parent: "national"},
+	{Slug: "world", DisplayName: "World", Aliases: []string{"international"}},
+	{Slug: "israel_hamas_war", DisplayName: "Israel-Hamas War", Parent: "world"},
+	{Slug: "russia_ukraine_conflict", DisplayName: "Russia-Ukraine Conflict", Parent: "world"},
+	{Slug: "defence", DisplayName: "Defence", Aliases: []string{"defense"}},
+	{Slug: "business", DisplayName: "Business", Aliases: []string{"finance", "economy"}},
+	{Slug: "startup", DisplayName: "Startups", Parent: "business", Aliases: []string{"startups"}},
+	{Slug: "automobile", DisplayName: "Automobile", Parent: "business", Aliases: []string{"auto"}},
+	{Slug: "technology", DisplayName: "Technology", Aliases: []string{"tech"}},
+	{Slug: "science", DisplayName: "Science"},
+	{Slug: "education", DisplayName: "Education"},
+	{Slug: "health_fitness", DisplayName: "Health & Fitness", Aliases: []string{"health", "fitness"}},
+	{Slug: "travel", DisplayName: "Travel"},
+	{Slug: "entertainment", DisplayName: "Entertainment"},
+	{Slug: "bollywood", DisplayName: "Bollywood", Parent: "entertainment"},
+	{Slug: "fashion", DisplayName: "Fashion", Parent: "entertainment"},
+	{Slug: "sports", DisplayName: "Sports", Aliases: []string{"sport"}},
+	{Slug: "cricket", DisplayName: "Cricket", Parent: "sports"},
+	{Slug: "ipl", DisplayName: "IPL", Parent: "cricket", Aliases: []string{"ipl_2025", "indian_premier_league"}},
+}
+
+type taxonomyCache struct {
+	mu       sync.RWMutex
+	loadedAt time.Time
+	bySlug   map[string]models.Category
+	aliases  map[string]string   // alias slug -> canonical slug
+	children map[string][]string // parent slug -> child slugs
+}
+
+var taxonomy taxonomyCache
+
+// SlugifyCategory lowercases a category name and joins its words with single
+// underscores, e.g. "Health___Fitness" -> "health_fitness".
+func SlugifyCategory(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(words, "_")
+}
+
+// EnsureDefaultTaxonomy seeds the categories collection when it is empty.
+func EnsureDefaultTaxonomy() error {
+	collection := database.GetCollection("categories")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to count categories: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var docs []interface{}
+	now := time.Now()
+	for _, category := range defaultTaxonomy {
+		category.UpdatedAt = now
+		if category.Aliases == nil {
+			category.Aliases = []string{}
+		}
+		docs = append(docs, category)
+	}
+	if _, err := collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to seed categories: %w", err)
+	}
+	taxonomy.invalidate()
+	return nil
+}
+
+func (t *taxonomyCache) invalidate() {
+	t.mu.Lock()
+	t.loadedAt = time.Time{}
+	t.mu.Unlock()
+}
+
+// get returns the cached taxonomy maps, reloading them when stale. If the
+// reload fails the previous maps are kept.
+func (t *taxonomyCache) get() (map[string]models.Category, map[string]string, map[string][]string) {
+	t.mu.RLock()
+	if time.Since(t.loadedAt) < taxonomyCacheTTL {
+		defer t.mu.RUnlock()
+		return t.bySlug, t.aliases, t.children
+	}
+	t.mu.RUnlock()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if time.Since(t.loadedAt) < taxonomyCacheTTL {
+		return t.bySlug, t.aliases, t.children
+	}
+
+	categories, err := loadCategories()
+	if err != nil {
+		slog.Error("Failed to load category taxonomy", "error", err)
+		return t.bySlug, t.aliases, t.children
+	}
+
+	t.bySlug = make(map[string]models.Category)
+	t.aliases = make(map[string]string)
+	t.children = make(map[string][]string)
+	for _, category := range categories {
+		t.bySlug[category.Slug] = category
+		for _, alias := range category.Aliases {
+			t.aliases[SlugifyCategory(alias)] = category.Slug
+		}
+		if category.Parent != "" {
+			t.children[category.Parent] = append(t.children[category.Parent], category.Slug)
+		}
+	}
+	t.loadedAt = time.Now()
+	return t.bySlug, t.aliases, t.children
+}
+
+func loadCategories() ([]models.Category, error) {
+	collection := database.GetCollection("categories")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find categories: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	categories := []models.Category{}
+	if err = cursor.All(ctx, &categories); err != nil {
+		return nil, fmt.Errorf("failed to decode categories: %w", err)
+	}
+	return categories, nil
+}
+
+// NormalizeCategory maps a category name or alias to its canonical slug.
+// Names outside the taxonomy are kept as their slug.
+func NormalizeCategory(name string) string {
+	slug := SlugifyCategory(name)
+	_, aliases, _ := taxonomy.get()
+	if canonical, found := aliases[slug]; found {
+		return canonical
+	}
+	return slug
+}
+
+// NormalizeCategories normalizes each category and drops duplicates and blanks.
+func NormalizeCategories(names []string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, name := range names {
+		slug := NormalizeCategory(name)
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		result = append(result, slug)
+	}
+	return result
+}
+
+// CategoryWithDescendants returns the canonical slug of name followed by the
+// slugs of all categories below it.
+func CategoryWithDescendants(name string) []string {
+	root := NormalizeCategory(name)
+	_, _, children := taxonomy.get()
+
+	result := []string{root}
+	seen := map[string]bool{root: true}
+	for i := 0; i < len(result); i++ {
+		for _, child := range children[result[i]] {
+			if !seen[child] {
+				seen[child] = true
+				result = append(result, child)
+			}
+		}
+	}
+	return result
+}
+
+// CategoryFilter matches articles in category, and in its descendants when
+// includeDescendants is set.
+func CategoryFilter(category string, includeDescendants bool) bson.M {
+	if includeDescendants {
+		return bson.M{"category": bson.M{"$in": CategoryWithDescendants(category)}}
+	}
+	return bson.M{"category": NormalizeCategory(category)}
+}
+
+// GetTaxonomyTree returns the taxonomy as a forest of root categories.
+func GetTaxonomyTree() []dto.CategoryNode {
+	bySlug, _, children := taxonomy.get()
+
+	var build func(slug string, depth int) dto.CategoryNode
+	build = func(slug string, depth int) dto.CategoryNode {
+		node := dto.CategoryNode{Category: bySlug[slug]}
+		if depth > len(bySlug) {
+			return node // guards against parent cycles
+		}
+		childSlugs := append([]string{}, children[slug]...)
+		sort.Strings(childSlugs)
+		for _, child := range childSlugs {
+			node.Children = append(node.Children, build(child, depth+1))
+		}
+		return node
+	}
+
+	var roots []string
+	for slug, category := range bySlug {
+		if _, hasParent := bySlug[category.Parent]; category.Parent == "" || !hasParent {
+			roots = append(roots, slug)
+		}
+	}
+	sort.Strings(roots)
+
+	tree := []dto.CategoryNode{}
+	for _, root := range roots {
+		tree = append(tree, build(root, 0))
+	}
+	return tree
+}
+
+// UpsertCategory creates or replaces a taxonomy category.
+func UpsertCategory(req *dto.UpsertCategoryRequest) (*models.Category, error) {
+	slug := SlugifyCategory(req.Slug)
+	if slug == "" {
+		return nil, apperror.Errorf(ErrInvalidCategory, "slug is empty")
+	}
+
+	bySlug, aliases, _ := taxonomy.get()
+	if owner, found := aliases[slug]; found && owner != slug {
+		return nil, apperror.Errorf(ErrInvalidCategory, "'%s' is already an alias of '%s'", slug, owner)
+	}
+	parent := ""
+	if req.Parent != "" {
+		parent = SlugifyCategory(req.Parent)
+		if _, found := bySlug[parent]; !found {
+			return nil, apperror.Errorf(ErrInvalidCategory, "parent '%s' does not exist", parent)
+		}
+		// Walk up from the parent to make sure slug is not one of its ancestors.
+		for ancestor := parent; ancestor != ""; ancestor = bySlug[ancestor].Parent {
+			if ancestor == slug {
+				return nil, apperror.Errorf(ErrInvalidCategory, "'%s' cannot be its own ancestor", slug)
+			}
+		}
+	}
+
+	categoryAliases := []string{}
+	for _, alias := range req.Aliases {
+		aliasSlug := SlugifyCategory(alias)
+		if aliasSlug == "" || aliasSlug == slug {
+			continue
+		}
+		if owner, found := aliases[aliasSlug]; found && owner != slug {
+			return nil, apperror.Errorf(ErrInvalidCategory, "alias '%s' already belongs to '%s'", aliasSlug, owner)
+		}
+		if _, found := bySlug[aliasSlug]; found {
+			return nil, apperror.Errorf(ErrInvalidCategory, "alias '%s' is a category itself", aliasSlug)
+		}
+		categoryAliases = append(categoryAliases, aliasSlug)
+	}
+
+	category := models.Category{
+		Slug:        slug,
+		DisplayName: req.DisplayName,
+		Aliases:     categoryAliases,
+		Parent:      parent,
+		UpdatedAt:   time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := database.GetCollection("categories").ReplaceOne(ctx, bson.M{"_id": slug}, category, options.Replace().SetUpsert(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store category: %w", err)
+	}
+	taxonomy.invalidate()
+	return &category, nil
+}
+
+// DeleteCategory removes a category that has no children. Articles keep the
+// slug, which is then treated as an unmanaged category.
+func DeleteCategory(slug string) error {
+	slug = SlugifyCategory(slug)
+	_, _, children := taxonomy.get()
+	if len(children[slug]) > 0 {
+		return apperror.Errorf(ErrInvalidCategory, "'%s' still has child categories", slug)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := database.GetCollection("categories").DeleteOne(ctx, bson.M{"_id": slug})
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrCategoryNotFound
+	}
+	taxonomy.invalidate()
+	return nil
+}
+
+// categoryMigration marks in the migrations collection that stored
+// categories were normalized.
+const categoryMigration = "normalize_categories"
+
+// NormalizeStoredCategories rewrites the categories of stored articles to
+// their canonical slugs, for articles ingested before the taxonomy existed.
+// It runs once; later taxonomy changes do not rewrite stored articles.
+func NormalizeStoredCategories() error {
+	collection := articleCollection()
+	migrations := database.GetCollection("migrations")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	err := migrations.FindOne(ctx, bson.M{"_id": categoryMigration}).Err()
+	if err == nil {
+		return nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return fmt.Errorf("failed to check category migration: %w", err)
+	}
+
+	values, err := collection.Distinct(ctx, "category", bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to get distinct categories: %w", err)
+	}
+
+	updated := int64(0)
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		canonical := NormalizeCategory(raw)
+		if canonical == raw || canonical == "" {
+			continue
+		}
+
+		// Two steps so an article already holding the canonical slug does not
+		// end up with it twice.
+		result, err := collection.UpdateMany(ctx, bson.M{"category": raw}, bson.M{"$addToSet": bson.M{"category": canonical}})
+		if err != nil {
+			return fmt.Errorf("failed to normalize category '%s': %w", raw, err)
+		}
+		if _, err := collection.UpdateMany(ctx, bson.M{"category": raw}, bson.M{"$pull": bson.M{"category": raw}}); err != nil {
+			return fmt.Errorf("failed to normalize category '%s': %w", raw, err)
+		}
+		updated += result.MatchedCount
+	}
+
+	if updated > 0 {
+		slog.Info("Normalized article categories", "count", updated)
+	}
+	// Recorded last, so an interrupted run is resumed on the next start.
+	_, err = migrations.InsertOne(ctx, bson.M{"_id": categoryMigration, "completed_at": time.Now()})
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to record category migration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"net/http"
+	"news-api/internal/config"
+	"news-api/internal/database"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// settings is the configuration the services run with. It holds the defaults
+// until Configure is called at startup.
+var settings = config.Default()
+
+// enrichmentClient calls the embedding and summarization service.
+var enrichmentClient = newEnrichmentClient(settings.Enrichment)
+
+// Configure sets the configuration used by the services.
+func Configure(cfg *config.Config) {
+	settings = cfg
+	enrichmentClient = newEnrichmentClient(cfg.Enrichment)
+}
+
+// newEnrichmentClient returns a client that traces its requests and
+// propagates the trace context to the service.
+func newEnrichmentClient(cfg config.EnrichmentConfig) *http.Client {
+	return &http.Client{
+		Timeout: cfg.Timeout.Duration,
+		Transport: otelhttp.NewTransport(http.DefaultTransport,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return "enrichment " + r.URL.Path
+			})),
+	}
+}
+
+// articleCollection is the collection of live articles.
+func articleCollection() *mongo.Collection {
+	return database.GetCollection(settings.Mongo.ArticlesCollection)
+}
+
+// archiveCollection is the collection of archived articles.
+func archiveCollection() *mongo.Collection {
+	return database.GetCollection(settings.Mongo.ArchiveCollection())
+}
+
+// TrendingWindow returns the duration of a named trending window.
+func TrendingWindow(name string) (time.Duration, bool) {
+	window, ok := settings.Trending.Windows[name]
+	return window.Duration, ok
+}
+
+// TrendingWindowNames lists the configured trending windows, shortest first.
+func TrendingWindowNames() []string {
+	names := make([]string, 0, len(settings.Trending.Windows))
+	for name := range settings.Trending.Windows {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return settings.Trending.Windows[names[i]].Duration < settings.Trending.Windows[names[j]].Duration
+	})
+	return names
+}
+
+// DefaultTrendingLimit is the number of trending articles precomputed per
+// window and returned when no limit is given.
+func DefaultTrendingLimit() int {
+	return settings.Trending.Limit
+}
+
+func trendingCacheKey(window string) string {
+	return "trending:articles:" + window
+}
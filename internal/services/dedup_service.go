@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math"
+	"math/bits"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// An embedding this close is treated as the same story on its own.
+	duplicateCosineThreshold = 0.95
+	// Between this and duplicateCosineThreshold the SimHash must agree as well.
+	nearDuplicateCosineThreshold = 0.88
+	// Maximum number of differing SimHash bits (out of 64) for a near-duplicate.
+	simHashMaxDistance = 12
+	// Titles/descriptions this close are duplicates regardless of embeddings.
+	simHashExactDistance = 3
+	// Only articles published within this window of each other are compared.
+	duplicateWindow = 72 * time.Hour
+	// Number of candidates pulled from the database for comparison.
+	duplicateCandidateLimit = 10
+)
+
+// computeSimHash returns a 64-bit SimHash fingerprint of text built from word
+// unigrams and bigrams, so that lightly reworded copies of the same story end
+// up a small Hamming distance apart.
+func computeSimHash(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+	addFeature := func(feature string) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	for i, word := range words {
+		addFeature(word)
+		if i > 0 {
+			addFeature(words[i-1] + " " + word)
+		}
+	}
+
+	var fingerprint uint64
+	for i := 0; i < 64; i++ {
+		if weights[i] > 0 {
+			fingerprint |= 1 << uint(i)
+		}
+	}
+	return fingerprint
+}
+
+func hammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func isNearDuplicate(cosine float64, distance int) bool {
+	if distance <= simHashExactDistance {
+		return true
+	}
+	if cosine >= duplicateCosineThreshold {
+		return true
+	}
+	return cosine >= nearDuplicateCosineThreshold && distance <= simHashMaxDistance
+}
+
+// matchNearDuplicate returns the candidate most similar to article that
+// qualifies as a near-duplicate, or nil if none does.
+func matchNearDuplicate(article *models.Article, candidates []models.Article) *models.Article {
+	var best *models.Article
+	bestScore := -1.0
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.ID == article.ID {
+			continue
+		}
+		if !article.PublicationDate.IsZero() && !candidate.PublicationDate.IsZero() {
+			gap := article.PublicationDate.Sub(candidate.PublicationDate)
+			if gap > duplicateWindow || gap < -duplicateWindow {
+				continue
+			}
+		}
+
+		cosine := cosineSimilarity(article.VectorEmbedding, candidate.VectorEmbedding)
+		distance := hammingDistance(uint64(article.SimHash), uint64(candidate.SimHash))
+		if candidate.SimHash == 0 {
+			// Articles ingested before SimHash existed can only match on embeddings.
+			distance = 64
+		}
+		if !isNearDuplicate(cosine, distance) {
+			continue
+		}
+
+		score := cosine + float64(64-distance)/64
+		if score > bestScore {
+			best = candidate
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// findDuplicateCandidates loads the stored articles most likely to be
+// near-duplicates of article. It uses Atlas vector search when available and
+// falls back to recently published articles otherwise. Only visible articles
+// are candidates, so a draft never becomes the representative of a story.
+func findDuplicateCandidates(ctx context.Context, article *models.Article) ([]models.Article, error) {
+	collection := articleCollection()
+	projection := bson.M{
+		"_id":              1,
+		"source_name":      1,
+		"publication_date": 1,
+		"vector_embedding": 1,
+		"simhash":          1,
+		"duplicate_of":     1,
+		"story_id":         1,
+	}
+
+	now := time.Now()
+	var candidates []models.Article
+	if len(article.VectorEmbedding) > 0 {
+		pipeline := mongo.Pipeline{
+			vectorSearchStage(article.VectorEmbedding, duplicateCandidateLimit, 100, vectorVisibilityFilter(now)),
+			{{Key: "$project", Value: projection}},
+		}
+		cursor, err := collection.Aggregate(ctx, pipeline)
+		if err == nil {
+			defer cursor.Close(ctx)
+			if err = cursor.All(ctx, &candidates); err == nil {
+				return candidates, nil
+			}
+		}
+		slog.WarnContext(ctx, "Vector search for duplicate candidates failed, falling back to recent articles", "error", err)
+	}
+
+	filter := visibilityFilter(now)
+	if !article.PublicationDate.IsZero() {
+		filter["publication_date"] = bson.M{
+			"$gte": article.PublicationDate.Add(-duplicateWindow),
+			"$lte": article.PublicationDate.Add(duplicateWindow),
+		}
+	}
+	findOptions := options.Find().
+		SetProjection(projection).
+		SetSort(bson.M{"publication_date": -1}).
+		SetLimit(200)
+
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load duplicate candidates: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &candidates); err != nil {
+		return nil, fmt.Errorf("failed to decode duplicate candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// linkNearDuplicate sets SimHash, DuplicateOf and StoryID on a new article.
+// pending holds articles from the same batch that are not yet stored. A
+// failure to look up candidates is logged and the article is kept as its own
+// story rather than rejecting the ingest.
+func linkNearDuplicate(ctx context.Context, article *models.Article, pending []models.Article) {
+	article.SimHash = int64(computeSimHash(article.Title + " " + article.Description))
+	storyID := article.ID
+	article.StoryID = &storyID
+
+	candidates, err := findDuplicateCandidates(ctx, article)
+	if err != nil {
+		slog.WarnContext(ctx, "Skipping near-duplicate detection", "title", article.Title, "error", err)
+	}
+	candidates = append(candidates, pending...)
+
+	match := matchNearDuplicate(article, candidates)
+	if match == nil {
+		return
+	}
+
+	representative := match.ID
+	if match.DuplicateOf != nil {
+		representative = *match.DuplicateOf
+	}
+	article.DuplicateOf = &representative
+
+	if match.StoryID != nil {
+		storyID = *match.StoryID
+	} else {
+		storyID = representative
+	}
+	article.StoryID = &storyID
+}
+
+// attachAlsoReportedBy fills AlsoReportedBy on each representative article
+// with the sources of its stored near-duplicates.
+func attachAlsoReportedBy(ctx context.Context, articles []dto.NewsArticleResponse) error {
+	var ids []primitive.ObjectID
+	for _, article := range articles {
+		if article.DuplicateOf == nil {
+			ids = append(ids, article.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	collection := articleCollection()
+	findOptions := options.Find().
+		SetProjection(bson.M{"_id": 1, "source_name": 1, "url": 1, "publication_date": 1, "duplicate_of": 1}).
+		SetSort(bson.M{"publication_date": 1})
+
+	filter := mergeFilters(bson.M{"duplicate_of": bson.M{"$in": ids}}, visibilityFilter(time.Now()), blockedSourceFilter())
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to find duplicate articles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var duplicates []models.Article
+	if err = cursor.All(ctx, &duplicates); err != nil {
+		return fmt.Errorf("failed to decode duplicate articles: %w", err)
+	}
+
+	byRepresentative := make(map[primitive.ObjectID][]dto.AlsoReportedBy)
+	for _, duplicate := range duplicates {
+		byRepresentative[*duplicate.DuplicateOf] = append(byRepresentative[*duplicate.DuplicateOf], dto.AlsoReportedBy{
+			ID:              duplicate.ID,
+			SourceName:      duplicate.SourceName,
+			URL:             duplicate.URL,
+			PublicationDate: duplicate.PublicationDate,
+		})
+	}
+
+	for i := range articles {
+		articles[i].AlsoReportedBy = appendUniqueReports(articles[i].AlsoReportedBy, byRepresentative[articles[i].ID]...)
+	}
+	return nil
+}
+
+func appendUniqueReports(reports []dto.AlsoReportedBy, more ...dto.AlsoReportedBy) []dto.AlsoReportedBy {
+	for _, report := range more {
+		seen := false
+		for _, existing := range reports {
+			if existing.ID == report.ID {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// CollapseDuplicates keeps one article per near-duplicate group in an
+// already-fetched list and reports the other members as AlsoReportedBy.
+func CollapseDuplicates(articles []dto.NewsArticleResponse) ([]dto.NewsArticleResponse, error) {
+	var result []dto.NewsArticleResponse
+	position := make(map[primitive.ObjectID]int)
+
+	for _, article := range articles {
+		group := article.ID
+		if article.DuplicateOf != nil {
+			group = *article.DuplicateOf
+		}
+
+		if idx, found := position[group]; found {
+			result[idx].AlsoReportedBy = appendUniqueReports(result[idx].AlsoReportedBy, dto.AlsoReportedBy{
+				ID:              article.ID,
+				SourceName:      article.SourceName,
+				URL:             article.URL,
+				PublicationDate: article.PublicationDate,
+			})
+			continue
+		}
+		position[group] = len(result)
+		result = append(result, article)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := attachAlsoReportedBy(ctx, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"news-api/internal/database"
+	"news-api/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Export formats.
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+const (
+	// Documents fetched per cursor batch during an export.
+	exportBatchSize = 500
+	// Rows written between flushes of the output.
+	exportFlushEvery = 500
+)
+
+var articleCSVHeader = []string{
+	"id", "title", "description", "url", "canonical_url", "publication_date",
+	"source_name", "source_id", "category", "relevance_score", "latitude", "longitude",
+	"llm_summary", "duplicate_of", "story_id",
+}
+
+var eventCSVHeader = []string{
+	"id", "user_id", "article_id", "event_type", "timestamp", "latitude", "longitude", "metadata",
+}
+
+// ExportArticles streams the articles matching filter to w as CSV or NDJSON,
+// reading them through a cursor. vector_embedding is only included when
+// includeEmbedding is set. It returns the number of articles written.
+func ExportArticles(ctx context.Context, w io.Writer, filter primitive.M, format string, includeEmbedding bool) (int, error) {
+	projection := bson.M{"simhash": 0}
+	if !includeEmbedding {
+		projection["vector_embedding"] = 0
+	}
+	findOptions := options.Find().
+		SetProjection(projection).
+		SetSort(bson.M{"publication_date": -1}).
+		SetBatchSize(exportBatchSize)
+
+	cursor, err := articleCollection().Find(ctx, filter, findOptions)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query articles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	header := articleCSVHeader
+	if includeEmbedding {
+		header = append(append([]string{}, header...), "vector_embedding")
+	}
+
+	return streamCursor(ctx, cursor, w, format, header, func(decode func(interface{}) error) (interface{}, []string, error) {
+		var article models.Article
+		if err := decode(&article); err != nil {
+			return nil, nil, err
+		}
+		return article, articleCSVRow(article, includeEmbedding), nil
+	})
+}
+
+// ExportEvents streams the user events matching filter to w as CSV or NDJSON.
+func ExportEvents(ctx context.Context, w io.Writer, filter primitive.M, format string) (int, error) {
+	findOptions := options.Find().
+		SetSort(bson.M{"timestamp": 1}).
+		SetBatchSize(exportBatchSize)
+
+	cursor, err := database.GetCollection("user_events").Find(ctx, filter, findOptions)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	return streamCursor(ctx, cursor, w, format, eventCSVHeader, func(decode func(interface{}) error) (interface{}, []string, error) {
+		var event models.UserEvent
+		if err := decode(&event); err != nil {
+			return nil, nil, err
+		}
+		return event, eventCSVRow(event), nil
+	})
+}
+
+// streamCursor writes each cursor document as an NDJSON line or a CSV row,
+// flushing periodically so large exports reach the client progressively.
+func streamCursor(ctx context.Context, cursor *mongo.Cursor, w io.Writer, format string, header []string,
+	decode func(func(interface{}) error) (interface{}, []string, error)) (int, error) {
+
+	flusher, _ := w.(interface{ Flush() })
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	var csvWriter *csv.Writer
+	var encoder *json.Encoder
+	switch format {
+	case ExportFormatCSV:
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(header); err != nil {
+			return 0, err
+		}
+	case ExportFormatNDJSON:
+		encoder = json.NewEncoder(w)
+	default:
+		return 0, fmt.Errorf("unsupported export format '%s'", format)
+	}
+
+	count := 0
+	for cursor.Next(ctx) {
+		doc, row, err := decode(cursor.Decode)
+		if err != nil {
+			return count, fmt.Errorf("failed to decode document: %w", err)
+		}
+
+		if csvWriter != nil {
+			err = csvWriter.Write(row)
+		} else {
+			err = encoder.Encode(doc)
+		}
+		if err != nil {
+			return count, fmt.Errorf("failed to write export: %w", err)
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			if csvWriter != nil {
+				csvWriter.Flush()
+			}
+			flush()
+		}
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return count, fmt.Errorf("failed to write export: %w", err)
+		}
+	}
+	flush()
+	return count, cursor.Err()
+}
+
+func articleCSVRow(article models.Article, includeEmbedding bool) []string {
+	var latitude, longitude string
+	if len(article.Location.Coordinates) == 2 {
+		longitude = strconv.FormatFloat(article.Location.Coordinates[0], 'f', -1, 64)
+		latitude = strconv.FormatFloat(article.Location.Coordinates[1], 'f', -1, 64)
+	}
+
+	row := []string{
+		article.ID.Hex(),
+		article.Title,
+		article.Description,
+		article.URL,
+		article.CanonicalURL,
+		formatExportTime(article.PublicationDate),
+		article.SourceName,
+		article.SourceID,
+		strings.Join(article.Category, "|"),
+		strconv.FormatFloat(article.RelevanceScore, 'f', -1, 64),
+		latitude,
+		longitude,
+		article.LLMSummary,
+		hexOrEmpty(article.DuplicateOf),
+		hexOrEmpty(article.StoryID),
+	}
+	if includeEmbedding {
+		embedding, _ := json.Marshal(article.VectorEmbedding)
+		row = append(row, string(embedding))
+	}
+	return row
+}
+
+func eventCSVRow(event models.UserEvent) []string {
+	var latitude, longitude, metadata string
+	if len(event.Location.Coordinates) == 2 {
+		longitude = strconv.FormatFloat(event.Location.Coordinates[0], 'f', -1, 64)
+		latitude = strconv.FormatFloat(event.Location.Coordinates[1], 'f', -1, 64)
+	}
+	if len(event.Metadata) > 0 {
+		encoded, _ := json.Marshal(event.Metadata)
+		metadata = string(encoded)
+	}
+
+	return []string{
+		event.ID.Hex(),
+		event.UserID,
+		event.ArticleID,
+		event.EventType,
+		formatExportTime(event.Timestamp),
+		latitude,
+		longitude,
+		metadata,
+	}
+}
+
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func hexOrEmpty(id *primitive.ObjectID) string {
+	if id == nil {
+		return ""
+	}
+	return id.Hex()
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"news-api/internal/dto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Date histogram intervals, as $dateToString formats.
+var facetDateFormats = map[string]string{
+	"day":   "%Y-%m-%d",
+	"week":  "%G-W%V",
+	"month": "%Y-%m",
+}
+
+// Lower bounds of the relevance score buckets; the last bound is exclusive.
+var scoreBucketBoundaries = []float64{0, 0.2, 0.4, 0.6, 0.8, 1.0000001}
+
+// Maximum number of category and source values returned.
+const facetValueLimit = 50
+
+func IsValidFacetInterval(interval string) bool {
+	_, ok := facetDateFormats[interval]
+	return ok
+}
+
+// GetNewsFacets counts the articles matching filter per category, source,
+// publication date interval and relevance score bucket in one $facet
+// aggregation. opts restricts the filter the same way it does for listings.
+func GetNewsFacets(filter primitive.M, interval string, opts NewsQueryOptions) (*dto.NewsFacets, error) {
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	dateFormat, ok := facetDateFormats[interval]
+	if !ok {
+		return nil, fmt.Errorf("invalid interval '%s'", interval)
+	}
+	filter = mergeFilters(listingFilter(filter, opts))
+
+	countBy := func(field string) []bson.M {
+		return []bson.M{
+			{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			{"$limit": facetValueLimit},
+		}
+	}
+
+	pipeline := []interface{}{bson.M{"$match": filter}}
+	if opts.IncludeArchived {
+		pipeline = append(pipeline, archiveUnionStage(filter))
+	}
+	pipeline = append(pipeline, bson.M{"$facet": bson.M{
+		"total":    []bson.M{{"$count": "count"}},
+		"category": append([]bson.M{{"$unwind": "$category"}}, countBy("category")...),
+		"source":   countBy("source_name"),
+		"publication_date": []bson.M{
+			{"$match": bson.M{"publication_date": bson.M{"$type": "date", "$gt": time.Time{}}}},
+			{"$group": bson.M{
+				"_id":   bson.M{"$dateToString": bson.M{"format": dateFormat, "date": "$publication_date"}},
+				"count": bson.M{"$sum": 1},
+			}},
+			{"$sort": bson.M{"_id": 1}},
+		},
+		"relevance_score": []bson.M{
+			{"$bucket": bson.M{
+				"groupBy":    "$relevance_score",
+				"boundaries": scoreBucketBoundaries,
+				"default":    "other",
+				"output":     bson.M{"count": bson.M{"$sum": 1}},
+			}},
+		},
+	}})
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate facets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+		Category        []facetGroup `bson:"category"`
+		Source          []facetGroup `bson:"source"`
+		PublicationDate []facetGroup `bson:"publication_date"`
+		RelevanceScore  []struct {
+			ID    interface{} `bson:"_id"`
+			Count int         `bson:"count"`
+		} `bson:"relevance_score"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode facets: %w", err)
+	}
+
+	facets := &dto.NewsFacets{
+		Category:        []dto.FacetCount{},
+		Source:          []dto.FacetCount{},
+		PublicationDate: []dto.FacetCount{},
+		RelevanceScore:  []dto.ScoreBucket{},
+	}
+	if len(results) == 0 {
+		return facets, nil
+	}
+	result := results[0]
+
+	if len(result.Total) > 0 {
+		facets.Total = result.Total[0].Count
+	}
+	facets.Category = toFacetCounts(result.Category)
+	facets.Source = toFacetCounts(result.Source)
+	facets.PublicationDate = toFacetCounts(result.PublicationDate)
+
+	for _, bucket := range result.RelevanceScore {
+		lower, ok := bucket.ID.(float64)
+		if !ok {
+			continue // scores outside the boundaries
+		}
+		for i := 0; i < len(scoreBucketBoundaries)-1; i++ {
+			if scoreBucketBoundaries[i] == lower {
+				facets.RelevanceScore = append(facets.RelevanceScore, dto.ScoreBucket{
+					Min:   lower,
+					Max:   min(scoreBucketBoundaries[i+1], 1),
+					Count: bucket.Count,
+				})
+				break
+			}
+		}
+	}
+	return facets, nil
+}
+
+type facetGroup struct {
+	ID    interface{} `bson:"_id"`
+	Count int         `bson:"count"`
+}
+
+func toFacetCounts(groups []facetGroup) []dto.FacetCount {
+	counts := []dto.FacetCount{}
+	for _, group := range groups {
+		value, ok := group.ID.(string)
+		if !ok || value == "" {
+			continue
+		}
+		counts = append(counts, dto.FacetCount{Value: value, Count: group.Count})
+	}
+	return counts
+}
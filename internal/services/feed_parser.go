@@ -0,0 +1,215 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// parsedFeed is the format-independent content of an RSS or Atom document.
+type parsedFeed struct {
+	Title   string
+	Entries []feedEntry
+}
+
+type feedEntry struct {
+	Title       string
+	Link        string
+	Description string
+	Published   time.Time
+	Categories  []string
+}
+
+type rssDocument struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"` // RSS 1.0 (RDF) puts items next to the channel
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        string   `xml:"guid"`
+	Description string   `xml:"description"`
+	Encoded     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	PubDate     string   `xml:"pubDate"`
+	DCDate      string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Categories  []string `xml:"category"`
+}
+
+type atomDocument struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary    string `xml:"summary"`
+	Content    string `xml:"content"`
+	Published  string `xml:"published"`
+	Updated    string `xml:"updated"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+// parseFeed decodes an RSS 2.0, RSS 1.0 or Atom 1.0 document.
+func parseFeed(body []byte) (*parsedFeed, error) {
+	root, err := feedRootElement(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss", "RDF":
+		var doc rssDocument
+		if err := xml.Unmarshal(body, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+		}
+		feed := &parsedFeed{Title: cleanFeedText(doc.Channel.Title)}
+		for _, item := range append(doc.Channel.Items, doc.Items...) {
+			feed.Entries = append(feed.Entries, rssItemToEntry(item))
+		}
+		return feed, nil
+
+	case "feed":
+		var doc atomDocument
+		if err := xml.Unmarshal(body, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+		}
+		feed := &parsedFeed{Title: cleanFeedText(doc.Title)}
+		for _, entry := range doc.Entries {
+			feed.Entries = append(feed.Entries, atomEntryToEntry(entry))
+		}
+		return feed, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported feed format with root element <%s>", root)
+	}
+}
+
+func feedRootElement(body []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", fmt.Errorf("feed document is empty")
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read feed document: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func rssItemToEntry(item rssItem) feedEntry {
+	link := strings.TrimSpace(item.Link)
+	if link == "" && strings.HasPrefix(strings.TrimSpace(item.GUID), "http") {
+		link = strings.TrimSpace(item.GUID)
+	}
+	description := item.Description
+	if description == "" {
+		description = item.Encoded
+	}
+	published := parseFeedDate(item.PubDate)
+	if published.IsZero() {
+		published = parseFeedDate(item.DCDate)
+	}
+
+	entry := feedEntry{
+		Title:       cleanFeedText(item.Title),
+		Link:        link,
+		Description: cleanFeedText(description),
+		Published:   published,
+	}
+	for _, category := range item.Categories {
+		if category = cleanFeedText(category); category != "" {
+			entry.Categories = append(entry.Categories, category)
+		}
+	}
+	return entry
+}
+
+func atomEntryToEntry(item atomEntry) feedEntry {
+	var link string
+	for _, l := range item.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			link = strings.TrimSpace(l.Href)
+			break
+		}
+	}
+	description := item.Summary
+	if description == "" {
+		description = item.Content
+	}
+	published := parseFeedDate(item.Published)
+	if published.IsZero() {
+		published = parseFeedDate(item.Updated)
+	}
+
+	entry := feedEntry{
+		Title:       cleanFeedText(item.Title),
+		Link:        link,
+		Description: cleanFeedText(description),
+		Published:   published,
+	}
+	for _, category := range item.Categories {
+		if term := cleanFeedText(category.Term); term != "" {
+			entry.Categories = append(entry.Categories, term)
+		}
+	}
+	return entry
+}
+
+var feedDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseFeedDate(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// cleanFeedText strips markup and entities from feed text fields.
+func cleanFeedText(value string) string {
+	value = htmlTagPattern.ReplaceAllString(value, " ")
+	value = html.UnescapeString(value)
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(value, " "))
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"news-api/internal/apperror"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Largest feed document that will be read.
+	maxFeedSize = 10 << 20
+	// A failing feed is retried after this delay times its failure count.
+	feedBackoffStep = 15 * time.Minute
+	// Upper bound for the retry delay of a failing feed.
+	feedMaxBackoff = 6 * time.Hour
+	// Relevance score used when a feed does not define one.
+	defaultFeedRelevanceScore = 0.5
+	// Feed entries are ingested in chunks of this size.
+	feedIngestChunkSize = 100
+)
+
+var (
+	ErrFeedNotFound = apperror.NotFound("Feed not found")
+	ErrFeedExists   = apperror.Conflict("Feed already exists")
+)
+
+// FeedHTTPClient fetches feed documents. It can be replaced, e.g. to point at
+// httptest servers.
+var FeedHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// FeedPollResult reports the outcome of polling one feed.
+type FeedPollResult struct {
+	FeedID      primitive.ObjectID   `json:"feed_id"`
+	URL         string               `json:"url"`
+	Status      string               `json:"status"`
+	StatusCode  int                  `json:"status_code,omitempty"`
+	Error       string               `json:"error,omitempty"`
+	EntryCount  int                  `json:"entry_count"`
+	Created     int                  `json:"created"`
+	ItemResults []dto.BulkItemResult `json:"items,omitempty"`
+}
+
+func AddFeed(req *dto.AddFeedRequest) (*models.Feed, error) {
+	collection := database.GetCollection("feeds")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, bson.M{"url": req.URL}).Err()
+	if err == nil {
+		return nil, apperror.Errorf(ErrFeedExists, "%s", req.URL)
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to check for existing feed: %w", err)
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	feed := models.Feed{
+		ID:             primitive.NewObjectID(),
+		URL:            req.URL,
+		SourceName:     req.SourceName,
+		Category:       req.Category,
+		RelevanceScore: req.RelevanceScore,
+		Location: models.Location{
+			Type:        "Point",
+			Coordinates: []float64{req.Longitude, req.Latitude},
+		},
+		Enabled:   enabled,
+		CreatedAt: time.Now(),
+		Health:    models.FeedHealth{Status: "pending"},
+	}
+
+	if _, err := collection.InsertOne(ctx, feed); err != nil {
+		return nil, fmt.Errorf("failed to insert feed: %w", err)
+	}
+	return &feed, nil
+}
+
+func GetFeeds() ([]models.Feed, error) {
+	collection := database.GetCollection("feeds")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feeds: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	feeds := []models.Feed{}
+	if err = cursor.All(ctx, &feeds); err != nil {
+		return nil, fmt.Errorf("failed to decode feeds: %w", err)
+	}
+	return feeds, nil
+}
+
+func GetFeed(id primitive.ObjectID) (*models.Feed, error) {
+	collection := database.GetCollection("feeds")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var feed models.Feed
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&feed)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrFeedNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feed: %w", err)
+	}
+	return &feed, nil
+}
+
+func DeleteFeed(id primitive.ObjectID) error {
+	collection := database.GetCollection("feeds")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete feed: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrFeedNotFound
+	}
+	return nil
+}
+
+// PollAllFeeds polls every enabled feed that is not backing off after
+// failures. It is run by the cron scheduler and stops before the next feed
+// when ctx is cancelled.
+func PollAllFeeds(ctx context.Context) error {
+	feeds, err := GetFeeds()
+	if err != nil {
+		return fmt.Errorf("failed to load feeds for polling: %w", err)
+	}
+
+	now := time.Now()
+	for i := range feeds {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("feed polling interrupted: %w", err)
+		}
+		feed := &feeds[i]
+		if !feed.Enabled || feedBackingOff(feed, now) {
+			continue
+		}
+		result := PollFeed(ctx, feed)
+		slog.Info("Polled feed", "url", feed.URL, "status", result.Status, "entries", result.EntryCount, "created", result.Created)
+	}
+	return nil
+}
+
+func feedBackingOff(feed *models.Feed, now time.Time) bool {
+	if feed.Health.ConsecutiveFailures == 0 || feed.Health.LastFetchedAt == nil {
+		return false
+	}
+	backoff := time.Duration(feed.Health.ConsecutiveFailures) * feedBackoffStep
+	if backoff > feedMaxBackoff {
+		backoff = feedMaxBackoff
+	}
+	return now.Sub(*feed.Health.LastFetchedAt) < backoff
+}
+
+// PollFeed fetches a feed with a conditional GET, ingests new entries through
+// the bulk ingest path and records the outcome on the feed's health. When
+// ctx is cancelled it stops before the next chunk of entries; the poll is
+// then degraded, so the feed is fetched again in full next time.
+func PollFeed(ctx context.Context, feed *models.Feed) FeedPollResult {
+	result := FeedPollResult{FeedID: feed.ID, URL: feed.URL}
+	now := time.Now()
+	health := feed.Health
+	health.LastFetchedAt = &now
+	update := bson.M{}
+
+	parsed, statusCode, validators, err := fetchFeed(feed)
+	result.StatusCode = statusCode
+	health.LastStatusCode = statusCode
+
+	switch {
+	case err != nil:
+		result.Status = "error"
+		result.Error = err.Error()
+		health.Status = "error"
+		health.LastError = err.Error()
+		health.ConsecutiveFailures++
+
+	case parsed == nil: // 304 Not Modified
+		result.Status = "not_modified"
+		health.Status = "not_modified"
+		health.LastError = ""
+		health.ConsecutiveFailures = 0
+		health.LastSuccessAt = &now
+
+	default:
+		items := feedEntriesToItems(feed, parsed)
+		for start := 0; start < len(items) && ctx.Err() == nil; start += feedIngestChunkSize {
+			end := min(start+feedIngestChunkSize, len(items))
+			result.ItemResults = append(result.ItemResults, BulkAddNewsEntries(context.WithoutCancel(ctx), items[start:end])...)
+		}
+		result.EntryCount = len(items)
+		if recordIngestOutcome(&result, &health, now) {
+			update["etag"] = validators.Get("ETag")
+			update["last_modified"] = validators.Get("Last-Modified")
+		}
+		if parsed.Title != "" {
+			update["title"] = parsed.Title
+		}
+	}
+
+	update["health"] = health
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if _, err := database.GetCollection("feeds").UpdateOne(ctx, bson.M{"_id": feed.ID}, bson.M{"$set": update}); err != nil {
+		slog.Error("Failed to update feed health", "url", feed.URL, "error", err)
+	}
+	feed.Health = health
+	return result
+}
+
+// recordIngestOutcome sets the status and health of a poll whose entries were
+// ingested into result.ItemResults. When entries failed to store or enrich,
+// or were not ingested at all, the poll is "degraded" and it returns false:
+// the previous validators must be kept, so the next poll fetches the feed
+// again instead of getting a 304 and the failed entries are retried.
+func recordIngestOutcome(result *FeedPollResult, health *models.FeedHealth, now time.Time) bool {
+	result.Created = 0
+	failed := result.EntryCount - len(result.ItemResults)
+	for _, item := range result.ItemResults {
+		switch item.Status {
+		case dto.BulkStatusCreated:
+			result.Created++
+		case dto.BulkStatusFailed, dto.BulkStatusEnrichmentFailed:
+			failed++
+		}
+	}
+
+	health.ConsecutiveFailures = 0
+	health.LastEntryCount = result.EntryCount
+	health.LastCreatedCount = result.Created
+	health.TotalCreated += result.Created
+	if failed > 0 {
+		result.Status = "degraded"
+		result.Error = fmt.Sprintf("%d of %d entries failed to ingest", failed, result.EntryCount)
+		health.Status = "degraded"
+		health.LastError = result.Error
+		return false
+	}
+	result.Status = "ok"
+	health.Status = "ok"
+	health.LastError = ""
+	health.LastSuccessAt = &now
+	return true
+}
+
+// fetchFeed performs the conditional GET. It returns a nil feed and no error
+// when the server answers 304 Not Modified.
+func fetchFeed(feed *models.Feed) (*parsedFeed, int, http.Header, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to build feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+	if feed.ETag != "" {
+		req.Header.Set("If-None-Match", feed.ETag)
+	}
+	if feed.LastModified != "" {
+		req.Header.Set("If-Modified-Since", feed.LastModified)
+	}
+
+	resp, err := FeedHTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, resp.StatusCode, resp.Header, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, resp.Header, fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header, fmt.Errorf("failed to read feed body: %w", err)
+	}
+
+	parsed, err := parseFeed(body)
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header, err
+	}
+	return parsed, resp.StatusCode, resp.Header, nil
+}
+
+// feedEntriesToItems maps parsed entries to bulk ingest items, filling in the
+// feed's defaults for fields the entries do not carry.
+func feedEntriesToItems(feed *models.Feed, parsed *parsedFeed) []dto.BulkIngestItem {
+	sourceName := feed.SourceName
+	if sourceName == "" {
+		sourceName = parsed.Title
+	}
+	relevanceScore := feed.RelevanceScore
+	if relevanceScore == 0 {
+		relevanceScore = defaultFeedRelevanceScore
+	}
+	var longitude, latitude float64
+	if len(feed.Location.Coordinates) == 2 {
+		longitude, latitude = feed.Location.Coordinates[0], feed.Location.Coordinates[1]
+	}
+
+	items := make([]dto.BulkIngestItem, 0, len(parsed.Entries))
+	for i, entry := range parsed.Entries {
+		category := feed.Category
+		if len(category) == 0 {
+			category = entry.Categories
+		}
+		if len(category) == 0 {
+			category = []string{"General"}
+		}
+		description := entry.Description
+		if description == "" {
+			description = entry.Title
+		}
+		published := entry.Published
+		if published.IsZero() {
+			published = time.Now()
+		}
+
+		item := dto.BulkIngestItem{
+			Index: i,
+			Request: &dto.AddNewsRequest{
+				Title:           entry.Title,
+				Description:     description,
+				URL:             entry.Link,
+				PublicationDate: published.UTC().Format(time.RFC3339),
+				SourceName:      sourceName,
+				Category:        category,
+				RelevanceScore:  relevanceScore,
+				Latitude:        latitude,
+				Longitude:       longitude,
+			},
+		}
+		if entry.Title == "" {
+			item.Errors = append(item.Errors, "entry has no title")
+		}
+		if entry.Link == "" {
+			item.Errors = append(item.Errors, "entry has no link")
+		}
+		items = append(items, item)
+	}
+	return items
+}
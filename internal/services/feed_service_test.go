@@ -0,0 +1,198 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"testing"
+	"time"
+)
+
+const rssFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Example News</title>
+    <item>
+      <title>First story</title>
+      <link>https://example.com/first</link>
+      <description>&lt;p&gt;First description&lt;/p&gt;</description>
+      <pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate>
+      <category>World</category>
+    </item>
+    <item>
+      <title>Second story</title>
+      <link>https://example.com/second</link>
+    </item>
+  </channel>
+</rss>`
+
+const atomFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example Atom</title>
+  <entry>
+    <title>Atom story</title>
+    <link rel="alternate" href="https://example.com/atom"/>
+    <summary>Atom summary</summary>
+    <published>2006-01-02T15:04:05Z</published>
+    <category term="Technology"/>
+  </entry>
+</feed>`
+
+// serveFeed points FeedHTTPClient at a test server handled by handler and
+// returns a feed subscribed to it.
+func serveFeed(t *testing.T, handler http.HandlerFunc) *models.Feed {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := FeedHTTPClient
+	FeedHTTPClient = server.Client()
+	t.Cleanup(func() { FeedHTTPClient = client })
+
+	return &models.Feed{URL: server.URL + "/feed.xml"}
+}
+
+func TestFetchFeedRSS(t *testing.T) {
+	feed := serveFeed(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(rssFixture))
+	})
+
+	parsed, status, validators, err := fetchFeed(feed)
+	if err != nil {
+		t.Fatalf("fetchFeed: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want 200", status)
+	}
+	if got := validators.Get("ETag"); got != `"v1"` {
+		t.Errorf("ETag = %q, want %q", got, `"v1"`)
+	}
+	if parsed.Title != "Example News" || len(parsed.Entries) != 2 {
+		t.Fatalf("parsed = %q with %d entries, want Example News with 2", parsed.Title, len(parsed.Entries))
+	}
+
+	items := feedEntriesToItems(feed, parsed)
+	first := items[0].Request
+	if first.URL != "https://example.com/first" || first.SourceName != "Example News" {
+		t.Errorf("first item = %+v", first)
+	}
+	if first.Description != "First description" {
+		t.Errorf("description = %q, want HTML stripped", first.Description)
+	}
+	if len(first.Category) != 1 || first.Category[0] != "World" {
+		t.Errorf("category = %v, want [World]", first.Category)
+	}
+	// Entries without a description or category get the feed defaults.
+	second := items[1].Request
+	if second.Description != "Second story" || second.Category[0] != "General" || second.RelevanceScore != defaultFeedRelevanceScore {
+		t.Errorf("second item = %+v", second)
+	}
+}
+
+func TestFetchFeedAtom(t *testing.T) {
+	feed := serveFeed(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.Write([]byte(atomFixture))
+	})
+	feed.Category = []string{"Tech"}
+
+	parsed, _, _, err := fetchFeed(feed)
+	if err != nil {
+		t.Fatalf("fetchFeed: %v", err)
+	}
+	if parsed.Title != "Example Atom" || len(parsed.Entries) != 1 {
+		t.Fatalf("parsed = %q with %d entries, want Example Atom with 1", parsed.Title, len(parsed.Entries))
+	}
+	entry := parsed.Entries[0]
+	if entry.Link != "https://example.com/atom" || entry.Description != "Atom summary" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC); !entry.Published.Equal(want) {
+		t.Errorf("published = %v, want %v", entry.Published, want)
+	}
+
+	// The feed's category overrides the entry's.
+	items := feedEntriesToItems(feed, parsed)
+	if got := items[0].Request.Category; len(got) != 1 || got[0] != "Tech" {
+		t.Errorf("category = %v, want [Tech]", got)
+	}
+}
+
+func TestFetchFeedNotModified(t *testing.T) {
+	feed := serveFeed(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(rssFixture))
+	})
+	feed.ETag = `"v1"`
+
+	parsed, status, _, err := fetchFeed(feed)
+	if err != nil {
+		t.Fatalf("fetchFeed: %v", err)
+	}
+	if status != http.StatusNotModified || parsed != nil {
+		t.Errorf("got status %d and feed %v, want 304 and no feed", status, parsed)
+	}
+}
+
+func TestFetchFeedServerError(t *testing.T) {
+	feed := serveFeed(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	parsed, status, _, err := fetchFeed(feed)
+	if err == nil {
+		t.Fatal("fetchFeed succeeded, want an error")
+	}
+	if status != http.StatusServiceUnavailable || parsed != nil {
+		t.Errorf("got status %d and feed %v, want 503 and no feed", status, parsed)
+	}
+}
+
+func TestRecordIngestOutcome(t *testing.T) {
+	tests := []struct {
+		name           string
+		statuses       []string
+		wantStatus     string
+		wantValidators bool
+		wantCreated    int
+	}{
+		{"all created", []string{dto.BulkStatusCreated, dto.BulkStatusCreated}, "ok", true, 2},
+		{"duplicates and invalid entries", []string{dto.BulkStatusCreated, dto.BulkStatusDuplicate, dto.BulkStatusInvalid}, "ok", true, 1},
+		{"failed insert", []string{dto.BulkStatusCreated, dto.BulkStatusFailed}, "degraded", false, 1},
+		{"failed enrichment", []string{dto.BulkStatusEnrichmentFailed, dto.BulkStatusDuplicate}, "degraded", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FeedPollResult{EntryCount: len(tt.statuses)}
+			for _, status := range tt.statuses {
+				result.ItemResults = append(result.ItemResults, dto.BulkItemResult{Status: status})
+			}
+			health := models.FeedHealth{ConsecutiveFailures: 2, TotalCreated: 10}
+			now := time.Now()
+
+			keepValidators := recordIngestOutcome(&result, &health, now)
+			if keepValidators != tt.wantValidators {
+				t.Errorf("store validators = %v, want %v", keepValidators, tt.wantValidators)
+			}
+			if result.Status != tt.wantStatus || health.Status != tt.wantStatus {
+				t.Errorf("status = %q/%q, want %q", result.Status, health.Status, tt.wantStatus)
+			}
+			if result.Created != tt.wantCreated || health.TotalCreated != 10+tt.wantCreated {
+				t.Errorf("created = %d (total %d), want %d", result.Created, health.TotalCreated, tt.wantCreated)
+			}
+			if health.ConsecutiveFailures != 0 {
+				t.Errorf("consecutive failures = %d, want 0", health.ConsecutiveFailures)
+			}
+			if degraded := tt.wantStatus == "degraded"; degraded != (health.LastError != "") || degraded == (health.LastSuccessAt != nil) {
+				t.Errorf("last error = %q, last success = %v", health.LastError, health.LastSuccessAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"google.golang.org/api/option"
+)
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusDegraded    = "degraded"
+	HealthStatusUnavailable = "unavailable"
+
+	DependencyUp           = "up"
+	DependencyDown         = "down"
+	DependencyUnconfigured = "unconfigured"
+)
+
+// Gemini is checked against its remote API, so results are reused for this
+// long rather than calling it on every probe.
+const geminiCheckInterval = time.Minute
+
+type dependencyCheck struct {
+	name     string
+	required bool
+	check    func(ctx context.Context) error
+}
+
+var errUnconfigured = errors.New("not configured")
+
+// Redis is optional: without it requests are still served, but trending is
+// computed on every request, rate limits fall back to per-instance counters
+// and Idempotency-Key headers are ignored.
+func dependencyChecks() []dependencyCheck {
+	return []dependencyCheck{
+		{name: "mongodb", required: true, check: checkMongo},
+		{name: "redis", required: false, check: checkRedis},
+		{name: "enrichment", required: false, check: checkEnrichment},
+		{name: "gemini", required: false, check: checkGemini},
+	}
+}
+
+func checkMongo(ctx context.Context) error {
+	if database.Client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+	return database.Client.Ping(ctx, readpref.Primary())
+}
+
+func checkRedis(ctx context.Context) error {
+	if database.Rdb == nil {
+		return fmt.Errorf("client not initialized")
+	}
+	return database.Rdb.Ping(ctx).Err()
+}
+
+// checkEnrichment only checks that the sidecar answers HTTP; the status code
+// is ignored since it has no dedicated health route.
+func checkEnrichment(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, settings.Enrichment.BaseURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := enrichmentClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+var geminiHealth struct {
+	sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+func checkGemini(ctx context.Context) error {
+	if settings.Gemini.APIKey == "" {
+		return errUnconfigured
+	}
+
+	geminiHealth.Lock()
+	defer geminiHealth.Unlock()
+	if time.Since(geminiHealth.checkedAt) < geminiCheckInterval {
+		return geminiHealth.err
+	}
+
+	client, err := genai.NewClient(ctx, option.WithAPIKey(settings.Gemini.APIKey))
+	if err == nil {
+		_, err = client.GenerativeModel(settings.Gemini.Model).Info(ctx)
+		client.Close()
+	}
+	geminiHealth.checkedAt, geminiHealth.err = time.Now(), err
+	return err
+}
+
+// CheckReadiness checks all dependencies concurrently, each within the
+// configured timeout. The service is unavailable when a required dependency
+// is down and degraded when only optional ones are.
+func CheckReadiness() dto.HealthReport {
+	checks := dependencyChecks()
+	results := make([]dto.DependencyHealth, len(checks))
+
+	var wg sync.WaitGroup
+	for i, dependency := range checks {
+		wg.Add(1)
+		go func(i int, dependency dependencyCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), settings.Health.Timeout.Duration)
+			defer cancel()
+
+			start := time.Now()
+			err := dependency.check(ctx)
+			result := dto.DependencyHealth{
+				Name:      dependency.name,
+				Status:    DependencyUp,
+				Required:  dependency.required,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err == errUnconfigured {
+				result.Status = DependencyUnconfigured
+			} else if err != nil {
+				// The endpoint is public, so the cause is only logged.
+				result.Status = DependencyDown
+				slog.WarnContext(ctx, "Dependency check failed", "dependency", dependency.name, "error", err)
+			}
+			results[i] = result
+		}(i, dependency)
+	}
+	wg.Wait()
+
+	report := dto.HealthReport{Status: HealthStatusOK, Dependencies: results, CheckedAt: time.Now()}
+	for _, result := range results {
+		if result.Status != DependencyDown {
+			continue
+		}
+		if result.Required {
+			report.Status = HealthStatusUnavailable
+			break
+		}
+		report.Status = HealthStatusDegraded
+	}
+	return report
+}
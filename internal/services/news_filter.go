@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"news-api/internal/dto"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BuildNewsFilter turns listing filter parameters into a Mongo filter.
+func BuildNewsFilter(params dto.NewsFilterParams) (primitive.M, error) {
+	var clauses []primitive.M
+
+	if params.Category != "" {
+		clauses = append(clauses, CategoryFilter(params.Category, params.IncludeDescendants))
+	}
+	if params.Source != "" {
+		clauses = append(clauses, SourceFilter(params.Source))
+	}
+	if params.MinScore != nil {
+		clauses = append(clauses, primitive.M{"relevance_score": primitive.M{"$gte": *params.MinScore}})
+	}
+	if params.Query != "" {
+		pattern := regexp.QuoteMeta(params.Query)
+		clauses = append(clauses, primitive.M{"$or": []primitive.M{
+			{"title": primitive.Regex{Pattern: pattern, Options: "i"}},
+			{"description": primitive.Regex{Pattern: pattern, Options: "i"}},
+		}})
+	}
+
+	if params.Lat != nil || params.Lon != nil || params.Radius != nil {
+		if params.Lat == nil || params.Lon == nil || params.Radius == nil {
+			return nil, fmt.Errorf("lat, lon and radius must be given together")
+		}
+		if *params.Radius <= 0 {
+			return nil, fmt.Errorf("radius must be positive")
+		}
+		clauses = append(clauses, primitive.M{
+			"location": primitive.M{
+				"$geoWithin": primitive.M{
+					"$centerSphere": []interface{}{
+						[]float64{*params.Lon, *params.Lat},
+						*params.Radius / 6378.1, // Convert km to radians (Earth's radius in km)
+					},
+				},
+			},
+		})
+	}
+
+	dateRange, err := buildDateRange(params.From, params.To)
+	if err != nil {
+		return nil, err
+	}
+	if dateRange != nil {
+		clauses = append(clauses, primitive.M{"publication_date": dateRange})
+	}
+
+	return mergeFilters(clauses...), nil
+}
+
+// BuildEventFilter turns event filter parameters into a Mongo filter.
+func BuildEventFilter(params dto.EventFilterParams) (primitive.M, error) {
+	filter := primitive.M{}
+	if params.ArticleID != "" {
+		filter["article_id"] = params.ArticleID
+	}
+	if params.UserID != "" {
+		filter["user_id"] = params.UserID
+	}
+	if params.EventType != "" {
+		filter["event_type"] = params.EventType
+	}
+
+	dateRange, err := buildDateRange(params.From, params.To)
+	if err != nil {
+		return nil, err
+	}
+	if dateRange != nil {
+		filter["timestamp"] = dateRange
+	}
+	return filter, nil
+}
+
+func buildDateRange(from, to string) (primitive.M, error) {
+	dateRange := primitive.M{}
+	if from != "" {
+		t := parseTime(from)
+		if t.IsZero() {
+			return nil, fmt.Errorf("invalid from date '%s'", from)
+		}
+		dateRange["$gte"] = t
+	}
+	if to != "" {
+		t := parseTime(to)
+		if t.IsZero() {
+			return nil, fmt.Errorf("invalid to date '%s'", to)
+		}
+		dateRange["$lte"] = t
+	}
+	if len(dateRange) == 0 {
+		return nil, nil
+	}
+	return dateRange, nil
+}
@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"news-api/internal/database"
+	"news-api/internal/apperror"
 	"news-api/internal/dto"
+	"news-api/internal/metrics"
 	"news-api/internal/models"
+	"news-api/internal/tracing"
+	"news-api/internal/utils"
 	"strings" // Added for string manipulation
 	"time"
 
@@ -19,22 +24,40 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func GetEmbeddingsfromText(text string) ([]float64, error) {
-	embedURL := "http://localhost:8001/embed"
+// ErrArticleExists is returned when an article with the same canonical URL is
+// already stored.
+var ErrArticleExists = apperror.Conflict("Article already exists")
+
+// postEnrichment posts a JSON body to the enrichment service. The trace
+// context of ctx is propagated to the service.
+func postEnrichment(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return enrichmentClient.Do(req)
+}
+
+func GetEmbeddingsfromText(ctx context.Context, text string) (embedding []float64, err error) {
+	defer func(start time.Time) { metrics.ObserveExternalCall(metrics.ServiceEmbedding, start, err) }(time.Now())
+
+	embedURL := settings.Enrichment.BaseURL + "/embed"
 	requestBody, err := json.Marshal(map[string]string{"text": text})
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	resp, err := http.Post(embedURL, "application/json", bytes.NewBuffer(requestBody))
+	resp, err := postEnrichment(ctx, embedURL, requestBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call embedding service: %w", err)
+		return nil, apperror.Upstream("embedding service unavailable", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("embedding service failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, apperror.Upstream("embedding service unavailable",
+			fmt.Errorf("status %d: %s", resp.StatusCode, string(body)))
 	}
 
 	var result struct {
@@ -47,27 +70,30 @@ func GetEmbeddingsfromText(text string) ([]float64, error) {
 	return result.Embedding, nil
 }
 
-func GetLLMSummaryFromURL(articleURL string) (string, error) {
+func GetLLMSummaryFromURL(ctx context.Context, articleURL string) (summary string, err error) {
 	// If the URL contains "youtube", return an empty string
 	if strings.Contains(articleURL, "youtube.com") || strings.Contains(articleURL, "youtu.be") {
 		return "", nil
 	}
 
-	summarizeURL := "http://localhost:8001/summarize"
+	defer func(start time.Time) { metrics.ObserveExternalCall(metrics.ServiceSummarization, start, err) }(time.Now())
+
+	summarizeURL := settings.Enrichment.BaseURL + "/summarize"
 	requestBody, err := json.Marshal(map[string]string{"url": articleURL})
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal request body for summary: %w", err)
 	}
 
-	resp, err := http.Post(summarizeURL, "application/json", bytes.NewBuffer(requestBody))
+	resp, err := postEnrichment(ctx, summarizeURL, requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to call summarization service: %w", err)
+		return "", apperror.Upstream("summarization service unavailable", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("summarization service failed with status %d: %s", resp.StatusCode, string(body))
+		return "", apperror.Upstream("summarization service unavailable",
+			fmt.Errorf("status %d: %s", resp.StatusCode, string(body)))
 	}
 
 	var result struct {
@@ -86,33 +112,61 @@ func GetLLMSummaryFromURL(articleURL string) (string, error) {
 	return result.Summary, nil
 }
 
-func AddNewsEntry(req *dto.AddNewsRequest) (*models.Article, error) {
-	collection := database.GetCollection("news_articles")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// duplicateURLFilter matches articles stored under the same canonical URL, or
+// under the exact raw URL for articles ingested before canonicalization.
+func duplicateURLFilter(rawURL, canonicalURL string) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"canonical_url": canonicalURL},
+		{"url": rawURL},
+	}}
+}
 
-	// Check for duplicate URL
-	filter := bson.M{"url": req.URL}
-	var existingArticle models.Article
-	err := collection.FindOne(ctx, filter).Decode(&existingArticle)
-	if err == nil {
-		return nil, fmt.Errorf("article with URL '%s' already exists", req.URL)
+// urlCollections are the collections an ingested URL must not already be
+// stored in. Archived articles keep their URLs, so re-ingesting one does not
+// bring it back as a new article.
+func urlCollections() []*mongo.Collection {
+	return []*mongo.Collection{articleCollection(), archiveCollection()}
+}
+
+// articleURLExists reports whether an article is stored, or archived, under
+// the URL.
+func articleURLExists(ctx context.Context, rawURL, canonicalURL string) (bool, error) {
+	filter := duplicateURLFilter(rawURL, canonicalURL)
+	for _, collection := range urlCollections() {
+		err := collection.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"_id": 1})).Err()
+		if err == nil {
+			return true, nil
+		}
+		if err != mongo.ErrNoDocuments {
+			return false, err
+		}
 	}
-	if err != mongo.ErrNoDocuments {
-		return nil, fmt.Errorf("failed to check for existing article: %w", err)
+	return false, nil
+}
+
+// buildArticle creates the article document for an ingest request, enriching
+// it with a vector embedding and an LLM summary from the sidecar service.
+func buildArticle(ctx context.Context, req *dto.AddNewsRequest, canonicalURL string) (models.Article, error) {
+	if err := validateIngestRequest(req); err != nil {
+		return models.Article{}, err
 	}
 
 	// Calculate vector embedding
 	articleText := req.Title + " " + req.Description
-	embedding, err := GetEmbeddingsfromText(articleText)
+	embedding, err := GetEmbeddingsfromText(ctx, articleText)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get embedding: %w", err)
+		return models.Article{}, fmt.Errorf("failed to get embedding: %w", err)
 	}
 
 	// Calculate LLM Summary
-	llmSummary, err := GetLLMSummaryFromURL(req.URL)
+	llmSummary, err := GetLLMSummaryFromURL(ctx, req.URL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get LLM summary: %w", err)
+		return models.Article{}, fmt.Errorf("failed to get LLM summary: %w", err)
+	}
+
+	sourceName, sourceID := req.SourceName, ""
+	if source := ResolveSource(req.SourceName, req.URL); source != nil {
+		sourceName, sourceID = source.DisplayName, source.ID
 	}
 
 	article := models.Article{
@@ -120,10 +174,13 @@ func AddNewsEntry(req *dto.AddNewsRequest) (*models.Article, error) {
 		Title:           req.Title,
 		Description:     req.Description,
 		URL:             req.URL,
+		CanonicalURL:    canonicalURL,
 		PublicationDate: parseTime(req.PublicationDate),
-		SourceName:      req.SourceName,
-		Category:        req.Category,
+		SourceName:      sourceName,
+		SourceID:        sourceID,
+		Category:        NormalizeCategories(req.Category),
 		RelevanceScore:  req.RelevanceScore,
+		RankingScore:    req.RelevanceScore * SourceTrustWeight(sourceID),
 		Location: models.Location{
 			Type:        "Point",
 			Coordinates: []float64{req.Longitude, req.Latitude},
@@ -131,105 +188,350 @@ func AddNewsEntry(req *dto.AddNewsRequest) (*models.Article, error) {
 		LLMSummary:      llmSummary,
 		VectorEmbedding: embedding,
 	}
+	applyIngestStatus(&article, req)
+	return article, nil
+}
+
+// AddNewsEntry ingests one article. The write is not cancelled when the
+// caller's context is; ctx only carries the trace.
+func AddNewsEntry(ctx context.Context, req *dto.AddNewsRequest) (*models.Article, error) {
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	canonicalURL, err := utils.CanonicalizeURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for duplicate URL
+	exists, err := articleURLExists(ctx, req.URL, canonicalURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing article: %w", err)
+	}
+	if exists {
+		return nil, apperror.Errorf(ErrArticleExists, "%s", canonicalURL)
+	}
 
+	article, err := buildArticle(ctx, req, canonicalURL)
+	if err != nil {
+		return nil, err
+	}
+	linkNearDuplicate(ctx, &article, nil)
+
+	// The lookup above only saves enrichment work; the unique canonical_url
+	// index decides races with concurrent ingests of the same URL.
 	_, err = collection.InsertOne(ctx, article)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, apperror.Errorf(ErrArticleExists, "%s", canonicalURL)
+	}
 	if err != nil {
-		fmt.Printf("Failed to insert article: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to insert article", "error", err)
 		return nil, err
 	}
 
-	fmt.Println("Article added successfully!")
+	slog.InfoContext(ctx, "Article added", "article_id", article.ID.Hex())
 	return &article, nil
 }
 
-func AddNewsEntryList(req []*dto.AddNewsRequest) ([]models.Article, error) {
-	collection := database.GetCollection("news_articles")
-	ctx, cancel := context.WithTimeout(context.Background(), 60*10*time.Second)
+func AddNewsEntryList(ctx context.Context, req []*dto.AddNewsRequest) ([]models.Article, error) {
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 60*10*time.Second)
 	defer cancel()
 
 	var articlesToInsert []interface{}
 	var articlesAdded []models.Article
+	seenURLs := make(map[string]bool)
 
 	for _, value := range req {
-		// Check for duplicate URL
-		filter := bson.M{"url": value.URL}
-		var existingArticle models.Article
-		err := collection.FindOne(ctx, filter).Decode(&existingArticle)
-		if err == nil {
-			fmt.Printf("Skipping duplicate article with URL: %s\n", value.URL)
-			continue // Skip this article if it's a duplicate
+		canonicalURL, err := utils.CanonicalizeURL(value.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL for article '%s': %w", value.Title, err)
 		}
-		if err != mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("failed to check for existing article '%s': %w", value.Title, err)
+		if seenURLs[canonicalURL] {
+			slog.InfoContext(ctx, "Skipping duplicate article", "url", value.URL)
+			continue
 		}
+		seenURLs[canonicalURL] = true
 
-		// Calculate vector embedding for each article
-		articleText := value.Title + " " + value.Description
-		embedding, err := GetEmbeddingsfromText(articleText)
+		// Check for duplicate URL
+		exists, err := articleURLExists(ctx, value.URL, canonicalURL)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get embedding for article '%s': %w", value.Title, err)
+			return nil, fmt.Errorf("failed to check for existing article '%s': %w", value.Title, err)
 		}
-
-		// Calculate LLM Summary for each article
-		llmSummary, err := GetLLMSummaryFromURL(value.URL)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get LLM summary for article '%s': %w", value.Title, err)
+		if exists {
+			slog.InfoContext(ctx, "Skipping duplicate article", "url", value.URL)
+			continue // Skip this article if it's a duplicate
 		}
 
-		article := models.Article{
-			ID:              primitive.NewObjectID(),
-			Title:           value.Title,
-			Description:     value.Description,
-			URL:             value.URL,
-			PublicationDate: parseTime(value.PublicationDate),
-			SourceName:      value.SourceName,
-			Category:        value.Category,
-			RelevanceScore:  value.RelevanceScore,
-			Location: models.Location{
-				Type:        "Point",
-				Coordinates: []float64{value.Longitude, value.Latitude},
-			},
-			LLMSummary:      llmSummary,
-			VectorEmbedding: embedding,
+		article, err := buildArticle(ctx, value, canonicalURL)
+		if err != nil {
+			return nil, fmt.Errorf("article '%s': %w", value.Title, err)
 		}
+		linkNearDuplicate(ctx, &article, articlesAdded)
 
 		articlesToInsert = append(articlesToInsert, article)
 		articlesAdded = append(articlesAdded, article)
 	}
 
 	if len(articlesToInsert) > 0 {
-		_, err := collection.InsertMany(ctx, articlesToInsert)
+		_, err := collection.InsertMany(ctx, articlesToInsert, options.InsertMany().SetOrdered(false))
 		if err != nil {
-			fmt.Printf("Failed to insert articles: %v\n", err)
+			articlesAdded, err = withoutDuplicateKeyFailures(articlesAdded, err)
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to insert articles", "error", err)
 			return nil, err
 		}
-		fmt.Println("Articles added successfully!")
+		slog.InfoContext(ctx, "Articles added", "count", len(articlesAdded))
 	} else {
-		fmt.Println("No new articles to add (all were duplicates or invalid).")
+		slog.InfoContext(ctx, "No new articles to add (all were duplicates or invalid)")
 	}
 
 	return articlesAdded, nil
 }
 
-func FindNews(filter primitive.M, page, pageSize int64) ([]dto.NewsArticleResponse, error) {
-	collection := database.GetCollection("news_articles") // Assuming "news_articles" is your collection name
-	ctx, cancel := context.WithTimeout(context.Background(), 60*10*time.Second)
+// withoutDuplicateKeyFailures returns the articles an unordered insert
+// stored when its only failures were duplicate keys, i.e. concurrent ingests
+// of the same URL that won the race. Any other failure is returned as is.
+func withoutDuplicateKeyFailures(articles []models.Article, err error) ([]models.Article, error) {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return nil, err
+	}
+	failed := make(map[int]bool, len(bulkErr.WriteErrors))
+	for _, writeErr := range bulkErr.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			return nil, err
+		}
+		failed[writeErr.Index] = true
+	}
+
+	stored := make([]models.Article, 0, len(articles)-len(failed))
+	for i, article := range articles {
+		if failed[i] {
+			slog.Info("Skipping duplicate article", "url", article.URL)
+			continue
+		}
+		stored = append(stored, article)
+	}
+	return stored, nil
+}
+
+// NewsQueryOptions controls how listing queries are shaped beyond their filter.
+type NewsQueryOptions struct {
+	// Collapse returns only representative articles of near-duplicate groups,
+	// with the other members listed in AlsoReportedBy.
+	Collapse bool
+	// Sort overrides the default ranking order.
+	Sort bson.D
+	// IncludeArchived also searches the article archive.
+	IncludeArchived bool
+}
+
+// mergeFilters combines the non-empty filters with $and.
+func mergeFilters(filters ...primitive.M) primitive.M {
+	var clauses []primitive.M
+	for _, f := range filters {
+		if len(f) > 0 {
+			clauses = append(clauses, f)
+		}
+	}
+	switch len(clauses) {
+	case 0:
+		return primitive.M{}
+	case 1:
+		return clauses[0]
+	default:
+		return primitive.M{"$and": clauses}
+	}
+}
+
+// BackfillCanonicalURLs sets canonical_url on articles ingested before URL
+// canonicalization so that the duplicate check also covers them. Older
+// variants of one URL (tracking parameters, http and https) share a canonical
+// URL; the oldest article keeps it and the others get a null canonical_url,
+// which the unique index skips, and point duplicate_of at the oldest.
+func BackfillCanonicalURLs() error {
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetProjection(bson.M{"_id": 1, "url": 1}).
+		SetSort(bson.M{"_id": 1})
+	cursor, err := collection.Find(ctx, bson.M{"canonical_url": bson.M{"$exists": false}}, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to find articles without canonical URL: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	owners := make(map[string]primitive.ObjectID)
+	var batch []models.Article
+	updated, duplicates := 0, 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, d, err := backfillCanonicalURLBatch(ctx, collection, batch, owners)
+		updated += n
+		duplicates += d
+		batch = batch[:0]
+		return err
+	}
+	for cursor.Next(ctx) {
+		var article models.Article
+		if err := cursor.Decode(&article); err != nil {
+			return fmt.Errorf("failed to decode article: %w", err)
+		}
+		article.CanonicalURL, err = utils.CanonicalizeURL(article.URL)
+		if err != nil {
+			slog.Warn("Cannot canonicalize article URL", "article_id", article.ID.Hex(), "error", err)
+			continue
+		}
+		batch = append(batch, article)
+		if len(batch) == 500 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	slog.Info("Backfilled canonical URLs", "count", updated, "duplicates", duplicates)
+	return cursor.Err()
+}
+
+// backfillCanonicalURLBatch stores the canonical URLs of batch. owners maps
+// canonical URLs to the article holding them and is updated as the backfill
+// goes; URLs not in it yet are looked up among already canonicalized articles.
+// It returns the number of articles updated and how many of them were marked
+// as duplicates.
+func backfillCanonicalURLBatch(ctx context.Context, collection *mongo.Collection, batch []models.Article, owners map[string]primitive.ObjectID) (int, int, error) {
+	var unknown []string
+	for _, article := range batch {
+		if _, found := owners[article.CanonicalURL]; !found {
+			unknown = append(unknown, article.CanonicalURL)
+		}
+	}
+	if len(unknown) > 0 {
+		cursor, err := collection.Find(ctx,
+			bson.M{"canonical_url": bson.M{"$in": unknown}},
+			options.Find().SetProjection(bson.M{"_id": 1, "canonical_url": 1}))
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to find canonical URL owners: %w", err)
+		}
+		var existing []models.Article
+		if err = cursor.All(ctx, &existing); err != nil {
+			return 0, 0, fmt.Errorf("failed to decode canonical URL owners: %w", err)
+		}
+		for _, article := range existing {
+			owners[article.CanonicalURL] = article.ID
+		}
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(batch))
+	duplicates := 0
+	for _, article := range batch {
+		update := bson.M{"canonical_url": article.CanonicalURL}
+		if owner, found := owners[article.CanonicalURL]; found && owner != article.ID {
+			update = bson.M{"canonical_url": nil, "duplicate_of": owner}
+			duplicates++
+		} else {
+			owners[article.CanonicalURL] = article.ID
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": article.ID}).
+			SetUpdate(bson.M{"$set": update}))
+	}
+	if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return 0, 0, fmt.Errorf("failed to backfill canonical URLs: %w", err)
+	}
+	return len(writes), duplicates, nil
+}
+
+// EnsureCanonicalURLIndex creates the unique canonical_url index that
+// rejects concurrent ingests of the same URL. Run it after
+// BackfillCanonicalURLs, which resolves older duplicates.
+func EnsureCanonicalURLIndex() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	_, err := articleCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "canonical_url", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"canonical_url": bson.M{"$type": "string"}}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create canonical URL index: %w", err)
+	}
+	return nil
+}
+
+// ReaderFilter restricts filter to the articles readers may list: visible
+// articles of sources that are not blocked.
+func ReaderFilter(filter primitive.M) primitive.M {
+	return listingFilter(filter, NewsQueryOptions{})
+}
+
+// listingFilter adds the restrictions implied by opts to a listing filter.
+// Unpublished and expired articles and articles of blocked sources are always
+// excluded.
+func listingFilter(filter primitive.M, opts NewsQueryOptions) primitive.M {
+	filter = mergeFilters(filter, visibilityFilter(time.Now()), blockedSourceFilter())
+	if opts.Collapse {
+		filter = mergeFilters(filter, primitive.M{"duplicate_of": primitive.M{"$exists": false}})
+	}
+	return filter
+}
+
+func FindNews(ctx context.Context, filter primitive.M, page, pageSize int64) ([]dto.NewsArticleResponse, error) {
+	return FindNewsWithOptions(ctx, filter, page, pageSize, NewsQueryOptions{})
+}
+
+func FindNewsWithOptions(ctx context.Context, filter primitive.M, page, pageSize int64, opts NewsQueryOptions) (_ []dto.NewsArticleResponse, err error) {
+	ctx, span := tracing.Start(ctx, "FindNews")
+	defer func() { tracing.End(span, err) }()
+
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(ctx, 60*10*time.Second)
 	defer cancel()
 
-	findOptions := options.Find()
-	findOptions.SetSkip((page - 1) * pageSize)
-	findOptions.SetLimit(pageSize)
+	filter = listingFilter(filter, opts)
 
-	cursor, err := collection.Find(ctx, filter, findOptions)
+	sort := opts.Sort
+	if sort == nil {
+		sort = bson.D{{Key: "ranking_score", Value: -1}, {Key: "publication_date", Value: -1}}
+	}
+
+	var cursor *mongo.Cursor
+	if opts.IncludeArchived {
+		cursor, err = collection.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			archiveUnionStage(filter),
+			{{Key: "$sort", Value: sort}},
+			{{Key: "$skip", Value: (page - 1) * pageSize}},
+			{{Key: "$limit", Value: pageSize}},
+		})
+	} else {
+		findOptions := options.Find()
+		findOptions.SetSort(sort)
+		findOptions.SetSkip((page - 1) * pageSize)
+		findOptions.SetLimit(pageSize)
+		cursor, err = collection.Find(ctx, filter, findOptions)
+	}
 	if err != nil {
-		fmt.Printf("Failed to find articles: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to find articles", "error", err)
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var articles []models.Article
 	if err = cursor.All(ctx, &articles); err != nil {
-		fmt.Printf("Failed to decode articles: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to decode articles", "error", err)
 		return nil, err
 	}
 
@@ -238,64 +540,114 @@ func FindNews(filter primitive.M, page, pageSize int64) ([]dto.NewsArticleRespon
 		responseArticles = append(responseArticles, dto.NewNewsArticleResponse(article))
 	}
 
+	if opts.Collapse {
+		if err := attachAlsoReportedBy(ctx, responseArticles); err != nil {
+			slog.WarnContext(ctx, "Failed to attach duplicate sources", "error", err)
+		}
+	}
+
 	return responseArticles, nil
 }
 
-func FindNewsByVectorEmbedding(embedding []float64, page, pageSize int64) ([]dto.NewsArticleResponse, error) {
-	collection := database.GetCollection("news_articles")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// vectorSearchStage builds the Atlas $vectorSearch stage against vector_index.
+// filter, if not nil, is applied before limit and may only use fields indexed
+// as filter fields.
+func vectorSearchStage(embedding []float64, limit int64, numCandidates int64, filter bson.M) bson.D {
+	stage := bson.D{
+		{Key: "queryVector", Value: embedding},
+		{Key: "path", Value: "vector_embedding"},
+		{Key: "numCandidates", Value: numCandidates},
+		{Key: "limit", Value: limit},
+		{Key: "index", Value: "vector_index"},
+	}
+	if filter != nil {
+		stage = append(stage, bson.E{Key: "filter", Value: filter})
+	}
+	return bson.D{{Key: "$vectorSearch", Value: stage}}
+}
+
+// FindNewsByVectorEmbedding returns the visible articles most similar to
+// embedding, weighted by source trust. With opts.IncludeArchived the archive
+// is searched as well.
+func FindNewsByVectorEmbedding(ctx context.Context, embedding []float64, page, pageSize int64, opts NewsQueryOptions) (_ []dto.NewsArticleResponse, err error) {
+	ctx, span := tracing.Start(ctx, "VectorSearch")
+	defer func() { tracing.End(span, err) }()
+
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	pipeline := mongo.Pipeline{
+	// Hidden articles and blocked sources are filtered inside $vectorSearch,
+	// so they do not take up result slots. Every page up to the requested one
+	// is fetched so that $skip has results to skip.
+	limit := page * pageSize
+	filter := mergeFilters(vectorVisibilityFilter(time.Now()), blockedSourceFilter())
+	search := mongo.Pipeline{
+		vectorSearchStage(embedding, limit, max(100, 10*limit), filter),
 		{{
-			"$vectorSearch", bson.D{
-				{"queryVector", embedding},
-				{"path", "vector_embedding"},
-				{"numCandidates", 100},
-				{"limit", pageSize},
-				{"index", "vector_index"},
+			Key: "$addFields", Value: bson.D{
+				{Key: "score", Value: bson.D{{Key: "$meta", Value: "vectorSearchScore"}}},
 			},
 		}},
+	}
+	pipeline := append(mongo.Pipeline{}, search...)
+	if opts.IncludeArchived {
+		// The archive has its own vector_index, searched the same way
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll":     settings.Mongo.ArchiveCollection(),
+			"pipeline": search,
+		}}})
+	}
+	pipeline = append(pipeline, mongo.Pipeline{
+		// Weight similarity by source trust
 		{{
-			"$addFields", bson.D{
-				{"score", bson.D{{"$meta", "vectorSearchScore"}}},
+			Key: "$addFields", Value: bson.D{
+				{Key: "search_rank", Value: bson.D{{Key: "$multiply", Value: bson.A{"$score", trustWeightExpr()}}}},
 			},
 		}},
 		{{
-			"$skip", (page - 1) * pageSize,
+			Key: "$sort", Value: bson.D{{Key: "search_rank", Value: -1}},
+		}},
+		{{
+			Key: "$skip", Value: (page - 1) * pageSize,
 		}},
 		{{
-			"$limit", pageSize,
+			Key: "$limit", Value: pageSize,
 		}},
 		// Remove the restrictive $project stage or include ALL fields
 		{{
-			"$project", bson.D{
-				{"_id", 1},
-				{"title", 1},
-				{"description", 1},
-				{"url", 1},
-				{"publication_date", 1},
-				{"source_name", 1},
-				{"category", 1},
-				{"relevance_score", 1},
-				{"location", 1},
-				{"llm_summary", 1},
-				{"vector_embedding", 1}, // Include if needed
-				{"score", 1},
+			Key: "$project", Value: bson.D{
+				{Key: "_id", Value: 1},
+				{Key: "title", Value: 1},
+				{Key: "description", Value: 1},
+				{Key: "url", Value: 1},
+				{Key: "publication_date", Value: 1},
+				{Key: "source_name", Value: 1},
+				{Key: "source_id", Value: 1},
+				{Key: "category", Value: 1},
+				{Key: "relevance_score", Value: 1},
+				{Key: "ranking_score", Value: 1},
+				{Key: "location", Value: 1},
+				{Key: "llm_summary", Value: 1},
+				{Key: "vector_embedding", Value: 1}, // Include if needed
+				{Key: "duplicate_of", Value: 1},
+				{Key: "story_id", Value: 1},
+				{Key: "archived", Value: 1},
+				{Key: "score", Value: 1},
 			},
 		}},
-	}
+	}...)
 
 	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		fmt.Printf("Failed to perform vector search: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to perform vector search", "error", err)
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	var articles []models.Article
 	if err = cursor.All(ctx, &articles); err != nil {
-		fmt.Printf("Failed to decode articles from vector search: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to decode articles from vector search", "error", err)
 		return nil, err
 	}
 
@@ -303,7 +655,6 @@ func FindNewsByVectorEmbedding(embedding []float64, page, pageSize int64) ([]dto
 	for _, article := range articles {
 		responseArticles = append(responseArticles, dto.NewNewsArticleResponse(article))
 	}
-	fmt.Println(responseArticles, "responseArticles")
 
 	return responseArticles, nil
 }
@@ -322,12 +673,12 @@ func parseTime(dateStr string) time.Time {
 			return t
 		}
 	}
-	fmt.Printf("Warning: Could not parse date string '%s' with known layouts. Returning zero time.\n", dateStr)
+	slog.Warn("Could not parse date string with known layouts, using zero time", "date", dateStr)
 	return time.Time{} // Return zero time if parsing fails
 }
 
 func GetAllCategories() ([]string, error) {
-	collection := database.GetCollection("news_articles")
+	collection := articleCollection()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -352,23 +703,3 @@ func GetAllCategories() ([]string, error) {
 	}
 	return result, nil
 }
-
-func GetAllSourceNames() ([]string, error) {
-	collection := database.GetCollection("news_articles")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Use Distinct to get all unique source names
-	sourceNames, err := collection.Distinct(ctx, "source_name", bson.D{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get distinct source names: %w", err)
-	}
-
-	var result []string
-	for _, sourceName := range sourceNames {
-		if snStr, ok := sourceName.(string); ok {
-			result = append(result, snStr)
-		}
-	}
-	return result, nil
-}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"news-api/internal/apperror"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrPinNotFound = apperror.NotFound("Pin not found")
+	ErrInvalidPin  = apperror.Validation("Invalid pin")
+)
+
+// AddPin pins an existing article to a category or the trending feed.
+func AddPin(req *dto.AddPinRequest) (*models.Pin, error) {
+	articleID, err := primitive.ObjectIDFromHex(req.ArticleID)
+	if err != nil {
+		return nil, apperror.Errorf(ErrInvalidPin, "invalid article_id")
+	}
+
+	now := time.Now()
+	pin := models.Pin{
+		ID:        primitive.NewObjectID(),
+		ArticleID: articleID,
+		Feed:      req.Feed,
+		Region:    strings.ToLower(strings.TrimSpace(req.Region)),
+		Position:  req.Position,
+		StartsAt:  now,
+		CreatedAt: now,
+	}
+	if req.Feed == models.PinFeedCategory {
+		pin.Category = NormalizeCategory(req.Category)
+	}
+	if req.StartsAt != "" {
+		if pin.StartsAt = parseTime(req.StartsAt); pin.StartsAt.IsZero() {
+			return nil, apperror.Errorf(ErrInvalidPin, "invalid starts_at")
+		}
+	}
+	if req.EndsAt != "" {
+		endsAt := parseTime(req.EndsAt)
+		if endsAt.IsZero() || !endsAt.After(pin.StartsAt) {
+			return nil, apperror.Errorf(ErrInvalidPin, "ends_at must be a time after starts_at")
+		}
+		pin.EndsAt = &endsAt
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = articleCollection().FindOne(ctx, bson.M{"_id": articleID},
+		options.FindOne().SetProjection(bson.M{"_id": 1})).Err()
+	if err == mongo.ErrNoDocuments {
+		return nil, missingArticleError(ctx, articleID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find article: %w", err)
+	}
+
+	if _, err := database.GetCollection("pins").InsertOne(ctx, pin); err != nil {
+		return nil, fmt.Errorf("failed to insert pin: %w", err)
+	}
+	return &pin, nil
+}
+
+// GetPins lists pins, optionally only those active now.
+func GetPins(activeOnly bool) ([]models.Pin, error) {
+	filter := bson.M{}
+	if activeOnly {
+		filter = activePinFilter(time.Now())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.GetCollection("pins").Find(ctx, filter, options.Find().SetSort(pinSort()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pins: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	pins := []models.Pin{}
+	if err = cursor.All(ctx, &pins); err != nil {
+		return nil, fmt.Errorf("failed to decode pins: %w", err)
+	}
+	return pins, nil
+}
+
+func DeletePin(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := database.GetCollection("pins").DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete pin: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrPinNotFound
+	}
+	return nil
+}
+
+func activePinFilter(now time.Time) bson.M {
+	return bson.M{
+		"starts_at": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"ends_at": bson.M{"$exists": false}},
+			{"ends_at": bson.M{"$gt": now}},
+		},
+	}
+}
+
+func pinSort() bson.D {
+	return bson.D{{Key: "position", Value: 1}, {Key: "created_at", Value: -1}}
+}
+
+// GetPinnedArticles returns the visible articles pinned to a feed, in pin
+// order. category is only used for the category feed; pins without a region
+// apply to every region.
+func GetPinnedArticles(feed, category, region string) ([]models.Article, error) {
+	now := time.Now()
+	filter := activePinFilter(now)
+	filter["feed"] = feed
+	if feed == models.PinFeedCategory {
+		filter["category"] = NormalizeCategory(category)
+	}
+	regions := bson.A{nil, ""}
+	if region = strings.ToLower(strings.TrimSpace(region)); region != "" {
+		regions = append(regions, region)
+	}
+	filter["region"] = bson.M{"$in": regions}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := database.GetCollection("pins").Find(ctx, filter, options.Find().SetSort(pinSort()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pins: %w", err)
+	}
+	var pins []models.Pin
+	if err = cursor.All(ctx, &pins); err != nil {
+		return nil, fmt.Errorf("failed to decode pins: %w", err)
+	}
+	if len(pins) == 0 {
+		return nil, nil
+	}
+
+	var articleIDs []primitive.ObjectID
+	for _, pin := range pins {
+		articleIDs = append(articleIDs, pin.ArticleID)
+	}
+	articleFilter := mergeFilters(bson.M{"_id": bson.M{"$in": articleIDs}}, visibilityFilter(now), blockedSourceFilter())
+	cursor, err = articleCollection().Find(ctx, articleFilter,
+		options.Find().SetProjection(bson.M{"vector_embedding": 0}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pinned articles: %w", err)
+	}
+	var found []models.Article
+	if err = cursor.All(ctx, &found); err != nil {
+		return nil, fmt.Errorf("failed to decode pinned articles: %w", err)
+	}
+
+	byID := make(map[primitive.ObjectID]models.Article, len(found))
+	for _, article := range found {
+		byID[article.ID] = article
+	}
+	var articles []models.Article
+	seen := make(map[primitive.ObjectID]bool)
+	for _, pin := range pins {
+		if article, ok := byID[pin.ArticleID]; ok && !seen[pin.ArticleID] {
+			seen[pin.ArticleID] = true
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+// FindNewsWithPins returns a listing page with the given pinned articles
+// prepended on page 1. Pinned articles are excluded from the regular results
+// on every page, so regular pages keep their size and boundaries.
+func FindNewsWithPins(ctx context.Context, filter primitive.M, pinned []models.Article, page, pageSize int64, opts NewsQueryOptions) ([]dto.NewsArticleResponse, error) {
+	if len(pinned) > 0 {
+		var pinnedIDs []primitive.ObjectID
+		for _, article := range pinned {
+			pinnedIDs = append(pinnedIDs, article.ID)
+		}
+		filter = mergeFilters(filter, primitive.M{"_id": primitive.M{"$nin": pinnedIDs}})
+	}
+
+	articles, err := FindNewsWithOptions(ctx, filter, page, pageSize, opts)
+	if err != nil || page != 1 || len(pinned) == 0 {
+		return articles, err
+	}
+
+	result := make([]dto.NewsArticleResponse, 0, len(pinned)+len(articles))
+	for _, article := range pinned {
+		response := dto.NewNewsArticleResponse(article)
+		response.Pinned = true
+		result = append(result, response)
+	}
+	return append(result, articles...), nil
+}
+
+// MergePinnedTrending puts pinned articles ahead of a trending list and drops
+// them from the rest of it.
+func MergePinnedTrending(trending []models.TrendingArticle, pinned []models.Article) []models.TrendingArticle {
+	if len(pinned) == 0 {
+		return trending
+	}
+
+	merged := make([]models.TrendingArticle, 0, len(pinned)+len(trending))
+	pinnedIDs := make(map[string]bool)
+	for _, article := range pinned {
+		pinnedIDs[article.ID.Hex()] = true
+		entry := models.TrendingArticle{
+			ArticleID:       article.ID.Hex(),
+			Title:           article.Title,
+			Description:     article.Description,
+			URL:             article.URL,
+			SourceName:      article.SourceName,
+			SourceID:        article.SourceID,
+			LLMSummary:      article.LLMSummary,
+			PublicationDate: article.PublicationDate,
+			Pinned:          true,
+		}
+		if len(article.Category) > 0 {
+			entry.Category = article.Category[0]
+		}
+		merged = append(merged, entry)
+	}
+	for _, article := range trending {
+		if !pinnedIDs[article.ArticleID] {
+			merged = append(merged, article)
+		}
+	}
+	return merged
+}
+
+// SetBreaking flags or unflags an article as breaking news.
+func SetBreaking(id primitive.ObjectID, breaking bool, actor string) (*dto.NewsArticleResponse, error) {
+	update := bson.M{"$set": bson.M{"breaking": true, "breaking_at": time.Now()}}
+	if !breaking {
+		update = bson.M{"$unset": bson.M{"breaking": "", "breaking_at": ""}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	before, err := loadArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	article, err := updateArticleVersioned(ctx, before, update, models.VersionActionBreaking, actor, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.NewNewsArticleResponse(*article)
+	return &response, nil
+}
+
+// FindBreakingNews lists visible breaking articles, most recently flagged first.
+func FindBreakingNews(ctx context.Context, page, pageSize int64, opts NewsQueryOptions) ([]dto.NewsArticleResponse, error) {
+	opts.Sort = bson.D{{Key: "breaking_at", Value: -1}, {Key: "publication_date", Value: -1}}
+	return FindNewsWithOptions(ctx, primitive.M{"breaking": true}, page, pageSize, opts)
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"news-api/internal/database"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Collection of daily per-article event counts.
+	dailyEventsCollection = "article_events_daily"
+	// Articles moved to the archive per batch.
+	archiveBatchSize = 1000
+)
+
+// EnsureEventTTLIndex creates the TTL index on user_events when TTL mode is
+// enabled, or drops it when it is not.
+func EnsureEventTTLIndex() error {
+	collection := database.GetCollection("user_events")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	const indexName = "timestamp_ttl"
+	if !settings.Retention.EventTTL {
+		if _, err := collection.Indexes().DropOne(ctx, indexName); err != nil {
+			if cmdErr, ok := err.(mongo.CommandError); !ok || cmdErr.Code != 27 { // IndexNotFound
+				return fmt.Errorf("failed to drop event TTL index: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// A TTL index whose expiry changed has to be recreated.
+	_, _ = collection.Indexes().DropOne(ctx, indexName)
+	expireAfter := int32(eventTTL().Seconds())
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "timestamp", Value: 1}},
+		Options: options.Index().SetName(indexName).SetExpireAfterSeconds(expireAfter),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create event TTL index: %w", err)
+	}
+	return nil
+}
+
+// eventTTL is how long the TTL index keeps raw events. A day is only rolled
+// up by the first retention run after it ends, so events are kept for the
+// retention period plus a day and the longest gap between retention runs.
+func eventTTL() time.Duration {
+	retention := time.Duration(settings.Retention.EventRetentionDays) * 24 * time.Hour
+	return retention + 24*time.Hour + longestScheduleGap(settings.Cron.Retention)
+}
+
+// longestScheduleGap returns the longest time between two of the next runs
+// of a cron schedule, or a day when the schedule does not parse.
+func longestScheduleGap(spec string) time.Duration {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return 24 * time.Hour
+	}
+	var longest time.Duration
+	next := schedule.Next(time.Now())
+	// Enough runs to see every month of monthly schedules.
+	for i := 0; i < 13 && !next.IsZero(); i++ {
+		following := schedule.Next(next)
+		longest = max(longest, following.Sub(next))
+		next = following
+	}
+	return longest
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// RollupUserEvents aggregates raw events in [from, to) into daily
+// per-article counts. Whole days are recomputed and replaced, so the rollup
+// can be rerun safely as long as the raw events of those days still exist.
+func RollupUserEvents(ctx context.Context, from, to time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	countType := func(eventType string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$event_type", eventType}}, 1, 0}}}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"timestamp": bson.M{"$gte": startOfDay(from), "$lt": startOfDay(to)}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"article_id": "$article_id",
+				"date":       bson.M{"$dateTrunc": bson.M{"date": "$timestamp", "unit": "day"}},
+			},
+			"views":  countType("view"),
+			"clicks": countType("click"),
+			"shares": countType("share"),
+			"total":  bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":        bson.M{"$concat": bson.A{"$_id.article_id", ":", bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$_id.date"}}}},
+			"article_id": "$_id.article_id",
+			"date":       "$_id.date",
+			"views":      1,
+			"clicks":     1,
+			"shares":     1,
+			"total":      1,
+		}}},
+		{{Key: "$merge", Value: bson.M{"into": dailyEventsCollection, "whenMatched": "replace", "whenNotMatched": "insert"}}},
+	}
+
+	cursor, err := database.GetCollection("user_events").Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to roll up user events: %w", err)
+	}
+	return cursor.Close(ctx)
+}
+
+// ApplyEventRetention rolls up completed days and, unless a TTL index does
+// the pruning, deletes raw events older than the retention period once their
+// days are rolled up. It is run by the cron scheduler and stops when ctx is
+// cancelled.
+func ApplyEventRetention(ctx context.Context) error {
+	today := startOfDay(time.Now())
+	cutoff := today.AddDate(0, 0, -settings.Retention.EventRetentionDays)
+
+	// Days before the cutoff are rolled up in full before being deleted;
+	// yesterday is refreshed on every run.
+	from := today.AddDate(0, 0, -1)
+	var oldest struct {
+		Timestamp time.Time `bson:"timestamp"`
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	collection := database.GetCollection("user_events")
+	err := collection.FindOne(ctx, bson.M{}, options.FindOne().
+		SetSort(bson.M{"timestamp": 1}).
+		SetProjection(bson.M{"timestamp": 1})).Decode(&oldest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return fmt.Errorf("failed to find oldest event: %w", err)
+	}
+	if err == nil && oldest.Timestamp.Before(from) {
+		from = oldest.Timestamp
+	}
+
+	if err := RollupUserEvents(ctx, from, today); err != nil {
+		return err
+	}
+	if settings.Retention.EventTTL {
+		return nil
+	}
+
+	result, err := collection.DeleteMany(ctx, bson.M{"timestamp": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return fmt.Errorf("failed to prune user events: %w", err)
+	}
+	if result.DeletedCount > 0 {
+		slog.Info("Pruned user events", "count", result.DeletedCount, "before", cutoff.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// archiveFilter matches articles published before cutoff. Articles without a
+// publication date are matched by when they were stored, taken from _id.
+func archiveFilter(cutoff time.Time) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"publication_date": bson.M{"$gt": time.Time{}, "$lt": cutoff}},
+		bson.M{
+			"publication_date": bson.M{"$in": bson.A{nil, time.Time{}}},
+			"_id":              bson.M{"$lt": primitive.NewObjectIDFromTimestamp(cutoff)},
+		},
+	}}
+}
+
+// ArchiveOldArticles moves articles published before the archive cutoff from
+// the articles collection to the archive collection in batches. It is run by
+// the cron scheduler. When ctx is cancelled it stops before the next batch;
+// a batch that was started is always both copied and removed, so no article
+// is left in both collections.
+func ArchiveOldArticles(ctx context.Context) (int, error) {
+	if settings.Retention.ArchiveAfterMonths == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	articles := articleCollection()
+	cutoff := time.Now().AddDate(0, -settings.Retention.ArchiveAfterMonths, 0)
+	filter := archiveFilter(cutoff)
+
+	archived := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return archived, fmt.Errorf("article archival interrupted: %w", err)
+		}
+		cursor, err := articles.Find(ctx, filter, options.Find().SetLimit(archiveBatchSize))
+		if err != nil {
+			return archived, fmt.Errorf("failed to find articles to archive: %w", err)
+		}
+		var docs []bson.M
+		if err = cursor.All(ctx, &docs); err != nil {
+			return archived, fmt.Errorf("failed to decode articles to archive: %w", err)
+		}
+		if len(docs) == 0 {
+			return archived, nil
+		}
+
+		if err := archiveBatch(ctx, docs); err != nil {
+			return archived, err
+		}
+		archived += len(docs)
+	}
+}
+
+// archiveBatchTimeout bounds the copy and removal of one batch, which are not
+// cancelled with the job.
+const archiveBatchTimeout = 2 * time.Minute
+
+// archiveBatch copies docs to the archive collection and then removes them
+// from the articles collection.
+func archiveBatch(ctx context.Context, docs []bson.M) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), archiveBatchTimeout)
+	defer cancel()
+
+	now := time.Now()
+	ids := make([]interface{}, 0, len(docs))
+	inserts := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		doc["archived"] = true
+		doc["archived_at"] = now
+		ids = append(ids, doc["_id"])
+		inserts = append(inserts, doc)
+	}
+
+	// Copies left by a failed run are duplicates; anything else aborts before
+	// the originals are deleted.
+	if _, err := archiveCollection().InsertMany(ctx, inserts, options.InsertMany().SetOrdered(false)); err != nil {
+		if bulkErr, ok := err.(mongo.BulkWriteException); !ok || !onlyDuplicateKeyErrors(bulkErr) {
+			return fmt.Errorf("failed to copy articles to archive: %w", err)
+		}
+	}
+	if _, err := articleCollection().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return fmt.Errorf("failed to remove archived articles: %w", err)
+	}
+	return nil
+}
+
+func onlyDuplicateKeyErrors(err mongo.BulkWriteException) bool {
+	if err.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range err.WriteErrors {
+		if writeErr.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
+
+// archiveUnionStage adds the archived articles matching filter to a pipeline.
+func archiveUnionStage(filter primitive.M) bson.D {
+	return bson.D{{Key: "$unionWith", Value: bson.M{
+		"coll":     settings.Mongo.ArchiveCollection(),
+		"pipeline": bson.A{bson.M{"$match": filter}},
+	}}}
+}
@@ -0,0 +1,559 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"news-api/internal/apperror"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// The registry is cached in memory and reloaded after this long so that
+	// changes made through other instances are picked up.
+	sourceCacheTTL = 5 * time.Minute
+	// Trust weight of sources that do not set one, and of unregistered sources.
+	defaultTrustWeight = 1.0
+)
+
+var (
+	ErrSourceNotFound = apperror.NotFound("Source not found")
+	ErrInvalidSource  = apperror.Validation("Invalid source")
+)
+
+// defaultSources seeds an empty sources collection.
+var defaultSources = []models.Source{
+	{ID: "hindustan_times", DisplayName: "Hindustan Times", Aliases: []string{"HT", "Hindustantimes"}, Domains: []string{"hindustantimes.com"}, Country: "IN", Language: "en"},
+	{ID: "abp_news", DisplayName: "ABP News", Aliases: []string{"ABP", "Abplive", "ABP Live"}, Domains: []string{"abplive.com"}, Country: "IN", Language: "en"},
+	{ID: "ndtv", DisplayName: "NDTV", Aliases: []string{"NDTV News"}, Domains: []string{"ndtv.com"}, Country: "IN", Language: "en"},
+	{ID: "news18", DisplayName: "News18", Aliases: []string{"News 18", "CNN-News18"}, Domains: []string{"news18.com"}, Country: "IN", Language: "en"},
+	{ID: "times_of_india", DisplayName: "The Times of India", Aliases: []string{"Times of India", "TOI"}, Domains: []string{"timesofindia.indiatimes.com"}, Country: "IN", Language: "en"},
+	{ID: "the_hindu", DisplayName: "The Hindu", Aliases: []string{"Hindu"}, Domains: []string{"thehindu.com"}, Country: "IN", Language: "en"},
+	{ID: "indian_express", DisplayName: "The Indian Express", Aliases: []string{"Indian Express"}, Domains: []string{"indianexpress.com"}, Country: "IN", Language: "en"},
+	{ID: "india_today", DisplayName: "India Today", Domains: []string{"indiatoday.in"}, Country: "IN", Language: "en"},
+	{ID: "reuters", DisplayName: "Reuters", Domains: []string{"reuters.com"}, Language: "en"},
+	{ID: "bbc_news", DisplayName: "BBC News", Aliases: []string{"BBC"}, Domains: []string{"bbc.com", "bbc.co.uk"}, Country: "GB", Language: "en"},
+}
+
+type sourceCache struct {
+	mu       sync.RWMutex
+	loadedAt time.Time
+	byID     map[string]models.Source
+	byKey    map[string]string // name key -> source ID
+	byDomain map[string]string // domain -> source ID
+}
+
+var sourceRegistry sourceCache
+
+// sourceKey reduces a source name to lowercase letters and digits so that
+// "Hindustan Times", "Hindustantimes" and "HINDUSTAN-TIMES " compare equal.
+func sourceKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// normalizeDomain strips the scheme, port, path and "www." from a domain or URL.
+func normalizeDomain(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if strings.Contains(value, "://") {
+		if parsed, err := url.Parse(value); err == nil {
+			value = parsed.Hostname()
+		}
+	}
+	value = strings.SplitN(value, "/", 2)[0]
+	value = strings.SplitN(value, ":", 2)[0]
+	return strings.TrimPrefix(value, "www.")
+}
+
+// EnsureDefaultSources seeds the sources collection when it is empty.
+func EnsureDefaultSources() error {
+	collection := database.GetCollection("sources")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to count sources: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var docs []interface{}
+	now := time.Now()
+	for _, source := range defaultSources {
+		source.UpdatedAt = now
+		if source.Aliases == nil {
+			source.Aliases = []string{}
+		}
+		docs = append(docs, source)
+	}
+	if _, err := collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to seed sources: %w", err)
+	}
+	sourceRegistry.invalidate()
+	return nil
+}
+
+func (s *sourceCache) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// get returns the cached registry maps, reloading them when stale. If the
+// reload fails the previous maps are kept.
+func (s *sourceCache) get() (map[string]models.Source, map[string]string, map[string]string) {
+	s.mu.RLock()
+	if time.Since(s.loadedAt) < sourceCacheTTL {
+		defer s.mu.RUnlock()
+		return s.byID, s.byKey, s.byDomain
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.loadedAt) < sourceCacheTTL {
+		return s.byID, s.byKey, s.byDomain
+	}
+
+	sources, err := loadSources()
+	if err != nil {
+		slog.Error("Failed to load source registry", "error", err)
+		return s.byID, s.byKey, s.byDomain
+	}
+
+	s.byID = make(map[string]models.Source)
+	s.byKey = make(map[string]string)
+	s.byDomain = make(map[string]string)
+	for _, source := range sources {
+		s.byID[source.ID] = source
+		s.byKey[sourceKey(source.ID)] = source.ID
+		s.byKey[sourceKey(source.DisplayName)] = source.ID
+		for _, alias := range source.Aliases {
+			s.byKey[sourceKey(alias)] = source.ID
+		}
+		for _, domain := range source.Domains {
+			s.byDomain[normalizeDomain(domain)] = source.ID
+		}
+	}
+	s.loadedAt = time.Now()
+	return s.byID, s.byKey, s.byDomain
+}
+
+func loadSources() ([]models.Source, error) {
+	collection := database.GetCollection("sources")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sources: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	sources := []models.Source{}
+	if err = cursor.All(ctx, &sources); err != nil {
+		return nil, fmt.Errorf("failed to decode sources: %w", err)
+	}
+	return sources, nil
+}
+
+// ResolveSource finds the registry entry for an article by its source name,
+// falling back to the domain of its URL (including subdomains, so
+// "sports.ndtv.com" resolves to ndtv.com). It returns nil when nothing matches.
+func ResolveSource(sourceName, articleURL string) *models.Source {
+	byID, byKey, byDomain := sourceRegistry.get()
+
+	if key := sourceKey(sourceName); key != "" {
+		if id, found := byKey[key]; found {
+			source := byID[id]
+			return &source
+		}
+	}
+
+	host := normalizeDomain(articleURL)
+	for host != "" {
+		if id, found := byDomain[host]; found {
+			source := byID[id]
+			return &source
+		}
+		dot := strings.Index(host, ".")
+		if dot < 0 {
+			break
+		}
+		host = host[dot+1:]
+	}
+	return nil
+}
+
+// SourceFilter matches articles of the source a name resolves to, or the exact
+// source name (case-insensitive) when it resolves to none. Articles stored
+// before their source was registered have no source_id until
+// ResolveStoredSources runs, so they are matched by any of the source's names.
+func SourceFilter(name string) bson.M {
+	source := ResolveSource(name, "")
+	if source == nil {
+		return bson.M{"source_name": caseInsensitiveExact(name)}
+	}
+	names := bson.A{caseInsensitiveExact(name), caseInsensitiveExact(source.DisplayName)}
+	for _, alias := range source.Aliases {
+		names = append(names, caseInsensitiveExact(alias))
+	}
+	return bson.M{"$or": []bson.M{
+		{"source_id": source.ID},
+		{"source_id": bson.M{"$exists": false}, "source_name": bson.M{"$in": names}},
+	}}
+}
+
+func caseInsensitiveExact(value string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value) + "$", Options: "i"}
+}
+
+// GetSourceSummaries returns every registry entry with its article count,
+// followed by source names that no entry resolves.
+func GetSourceSummaries() ([]dto.SourceSummary, error) {
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pipeline := []bson.M{
+		{"$group": bson.M{
+			"_id":   bson.M{"source_id": "$source_id", "source_name": "$source_name"},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count articles per source: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		ID struct {
+			SourceID   string `bson:"source_id"`
+			SourceName string `bson:"source_name"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode source counts: %w", err)
+	}
+
+	byID, _, _ := sourceRegistry.get()
+	counts := make(map[string]int64)
+	unregistered := make(map[string]int64)
+	for _, group := range groups {
+		if _, found := byID[group.ID.SourceID]; found {
+			counts[group.ID.SourceID] += group.Count
+		} else if group.ID.SourceName != "" {
+			unregistered[group.ID.SourceName] += group.Count
+		}
+	}
+
+	summaries := []dto.SourceSummary{}
+	for id, source := range byID {
+		summaries = append(summaries, dto.SourceSummary{Source: source, Registered: true, ArticleCount: counts[id]})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].ID < summaries[j].ID })
+
+	var names []string
+	for name := range unregistered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		summaries = append(summaries, dto.SourceSummary{
+			Source:       models.Source{DisplayName: name},
+			ArticleCount: unregistered[name],
+		})
+	}
+	return summaries, nil
+}
+
+// UpsertSource creates or replaces a registry entry.
+func UpsertSource(req *dto.UpsertSourceRequest) (*models.Source, error) {
+	id := SlugifyCategory(req.ID)
+	if id == "" {
+		return nil, apperror.Errorf(ErrInvalidSource, "id is empty")
+	}
+
+	_, byKey, byDomain := sourceRegistry.get()
+	for _, name := range append([]string{req.DisplayName}, req.Aliases...) {
+		if owner, found := byKey[sourceKey(name)]; found && owner != id {
+			return nil, apperror.Errorf(ErrInvalidSource, "name '%s' already belongs to '%s'", name, owner)
+		}
+	}
+	domains := []string{}
+	for _, domain := range req.Domains {
+		domain = normalizeDomain(domain)
+		if domain == "" {
+			continue
+		}
+		if owner, found := byDomain[domain]; found && owner != id {
+			return nil, apperror.Errorf(ErrInvalidSource, "domain '%s' already belongs to '%s'", domain, owner)
+		}
+		domains = append(domains, domain)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Trust settings are changed through UpdateSourceTrust; an upsert that
+	// omits them keeps the stored ones.
+	var existing models.Source
+	err := database.GetCollection("sources").FindOne(ctx, bson.M{"_id": id}).Decode(&existing)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("failed to find source: %w", err)
+	}
+
+	source := models.Source{
+		ID:          id,
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		Aliases:     req.Aliases,
+		Domains:     domains,
+		Country:     strings.ToUpper(req.Country),
+		Language:    strings.ToLower(req.Language),
+		LogoURL:     req.LogoURL,
+		TrustWeight: existing.TrustWeight,
+		Blocked:     existing.Blocked,
+		UpdatedAt:   time.Now(),
+	}
+	if req.TrustWeight != nil {
+		source.TrustWeight = req.TrustWeight
+	}
+	if req.Blocked != nil {
+		source.Blocked = *req.Blocked
+	}
+	if source.Aliases == nil {
+		source.Aliases = []string{}
+	}
+
+	_, err = database.GetCollection("sources").ReplaceOne(ctx, bson.M{"_id": id}, source, options.Replace().SetUpsert(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store source: %w", err)
+	}
+	sourceRegistry.invalidate()
+
+	// Filters such as blockedSourceFilter only see source_id, so articles
+	// stored under one of the source's names before it existed join it now
+	if err := resolveArticlesOfSource(ctx, source); err != nil {
+		return nil, err
+	}
+	if err := applySourceTrustWeight(ctx, id, trustWeight(source)); err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// resolveArticlesOfSource points unresolved articles whose source name is the
+// display name or an alias of source at it.
+func resolveArticlesOfSource(ctx context.Context, source models.Source) error {
+	names := bson.A{caseInsensitiveExact(source.DisplayName)}
+	for _, alias := range source.Aliases {
+		names = append(names, caseInsensitiveExact(alias))
+	}
+	result, err := articleCollection().UpdateMany(ctx,
+		bson.M{"source_id": bson.M{"$exists": false}, "source_name": bson.M{"$in": names}},
+		bson.M{"$set": bson.M{"source_id": source.ID, "source_name": source.DisplayName}})
+	if err != nil {
+		return fmt.Errorf("failed to resolve articles of source '%s': %w", source.ID, err)
+	}
+	if result.ModifiedCount > 0 {
+		slog.InfoContext(ctx, "Resolved article sources", "source_id", source.ID, "count", result.ModifiedCount)
+	}
+	return nil
+}
+
+// UpdateSourceTrust changes the trust weight and/or blocked flag of a source
+// and recomputes the ranking scores of its articles.
+func UpdateSourceTrust(id string, req *dto.UpdateSourceTrustRequest) (*models.Source, error) {
+	set := bson.M{"updated_at": time.Now()}
+	if req.TrustWeight != nil {
+		set["trust_weight"] = *req.TrustWeight
+	}
+	if req.Blocked != nil {
+		set["blocked"] = *req.Blocked
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var source models.Source
+	err := database.GetCollection("sources").FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&source)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrSourceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update source: %w", err)
+	}
+	sourceRegistry.invalidate()
+
+	if req.TrustWeight != nil {
+		if err := applySourceTrustWeight(ctx, id, trustWeight(source)); err != nil {
+			return nil, err
+		}
+	}
+	return &source, nil
+}
+
+func trustWeight(source models.Source) float64 {
+	if source.TrustWeight == nil {
+		return defaultTrustWeight
+	}
+	return *source.TrustWeight
+}
+
+// SourceTrustWeight returns the trust weight of a source ID.
+func SourceTrustWeight(sourceID string) float64 {
+	byID, _, _ := sourceRegistry.get()
+	if source, found := byID[sourceID]; found {
+		return trustWeight(source)
+	}
+	return defaultTrustWeight
+}
+
+// IsSourceBlocked reports whether a source ID is blocked.
+func IsSourceBlocked(sourceID string) bool {
+	byID, _, _ := sourceRegistry.get()
+	return byID[sourceID].Blocked
+}
+
+// blockedSourceFilter excludes articles of blocked sources, or returns nil
+// when no source is blocked. Articles are matched by source_id, which
+// ResolveStoredSources and UpsertSource fill in for articles stored under a
+// source's names.
+func blockedSourceFilter() primitive.M {
+	byID, _, _ := sourceRegistry.get()
+	var blocked []string
+	for id, source := range byID {
+		if source.Blocked {
+			blocked = append(blocked, id)
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+	sort.Strings(blocked)
+	return primitive.M{"source_id": primitive.M{"$nin": blocked}}
+}
+
+// trustWeightExpr is an aggregation expression evaluating to the trust weight
+// of the article's source_id.
+func trustWeightExpr() interface{} {
+	byID, _, _ := sourceRegistry.get()
+	var branches []bson.M
+	for id, source := range byID {
+		if weight := trustWeight(source); weight != defaultTrustWeight {
+			branches = append(branches, bson.M{"case": bson.M{"$eq": bson.A{"$source_id", id}}, "then": weight})
+		}
+	}
+	if len(branches) == 0 {
+		return defaultTrustWeight
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": defaultTrustWeight}}
+}
+
+// applySourceTrustWeight recomputes ranking_score for the articles of a source.
+func applySourceTrustWeight(ctx context.Context, sourceID string, weight float64) error {
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"ranking_score": bson.M{"$multiply": bson.A{"$relevance_score", weight}},
+	}}}}
+	_, err := articleCollection().UpdateMany(ctx, bson.M{"source_id": sourceID}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update ranking scores: %w", err)
+	}
+	return nil
+}
+
+// BackfillRankingScores sets ranking_score on articles stored before it
+// existed.
+func BackfillRankingScores() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"ranking_score": bson.M{"$multiply": bson.A{"$relevance_score", trustWeightExpr()}},
+	}}}}
+	result, err := articleCollection().UpdateMany(ctx, bson.M{"ranking_score": bson.M{"$exists": false}}, update)
+	if err != nil {
+		return fmt.Errorf("failed to backfill ranking scores: %w", err)
+	}
+	if result.ModifiedCount > 0 {
+		slog.Info("Backfilled ranking scores", "count", result.ModifiedCount)
+	}
+	return nil
+}
+
+// DeleteSource removes a registry entry. Articles keep their source_id and
+// source_name.
+func DeleteSource(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := database.GetCollection("sources").DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete source: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return ErrSourceNotFound
+	}
+	sourceRegistry.invalidate()
+	return nil
+}
+
+// ResolveStoredSources sets source_id and the display name on stored
+// articles whose source name resolves to a registry entry.
+func ResolveStoredSources() error {
+	collection := articleCollection()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	values, err := collection.Distinct(ctx, "source_name", bson.M{"source_id": bson.M{"$exists": false}})
+	if err != nil {
+		return fmt.Errorf("failed to get distinct source names: %w", err)
+	}
+
+	updated := int64(0)
+	for _, value := range values {
+		name, ok := value.(string)
+		if !ok {
+			continue
+		}
+		source := ResolveSource(name, "")
+		if source == nil {
+			continue
+		}
+		result, err := collection.UpdateMany(ctx,
+			bson.M{"source_name": name, "source_id": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"source_id": source.ID, "source_name": source.DisplayName}})
+		if err != nil {
+			return fmt.Errorf("failed to resolve source '%s': %w", name, err)
+		}
+		updated += result.ModifiedCount
+	}
+
+	if updated > 0 {
+		slog.Info("Resolved article sources", "count", updated)
+	}
+	return nil
+}
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"news-api/internal/apperror"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Articles published within this lookback are (re)clustered on each run.
+	storyLookback = 7 * 24 * time.Hour
+	// Minimum centroid similarity for two groups to belong to one story.
+	storyCosineThreshold = 0.80
+	// Maximum gap between a story's latest article and the next one joining it.
+	storyMaxGap = 48 * time.Hour
+	// Clusters smaller than this are not stored as stories.
+	storyMinArticles = 2
+	// Writing one story is not cancelled with the job, but bounded by this.
+	storyWriteTimeout = 30 * time.Second
+)
+
+var ErrStoryNotFound = apperror.NotFound("Story not found")
+
+type storyCluster struct {
+	id       primitive.ObjectID
+	mergedID []primitive.ObjectID
+	articles []models.Article
+	centroid []float64
+	first    time.Time
+	last     time.Time
+}
+
+func (sc *storyCluster) add(articles ...models.Article) {
+	for _, article := range articles {
+		sc.articles = append(sc.articles, article)
+		if sc.first.IsZero() || article.PublicationDate.Before(sc.first) {
+			sc.first = article.PublicationDate
+		}
+		if article.PublicationDate.After(sc.last) {
+			sc.last = article.PublicationDate
+		}
+	}
+	sc.centroid = meanEmbedding(sc.articles)
+}
+
+func meanEmbedding(articles []models.Article) []float64 {
+	var sum []float64
+	count := 0
+	for _, article := range articles {
+		if len(article.VectorEmbedding) == 0 {
+			continue
+		}
+		if sum == nil {
+			sum = make([]float64, len(article.VectorEmbedding))
+		}
+		if len(article.VectorEmbedding) != len(sum) {
+			continue
+		}
+		for i, v := range article.VectorEmbedding {
+			sum[i] += v
+		}
+		count++
+	}
+	for i := range sum {
+		sum[i] /= float64(count)
+	}
+	return sum
+}
+
+// clusterArticles groups articles into stories. Articles that already share a
+// story_id (near-duplicates or earlier runs) start in the same cluster; the
+// clusters are then merged greedily in publication order when their centroids
+// are similar and they are close in time.
+func clusterArticles(articles []models.Article) []*storyCluster {
+	seeds := make(map[primitive.ObjectID]*storyCluster)
+	var ordered []*storyCluster
+	for _, article := range articles {
+		id := article.ID
+		if article.StoryID != nil {
+			id = *article.StoryID
+		}
+		cluster, found := seeds[id]
+		if !found {
+			cluster = &storyCluster{id: id}
+			seeds[id] = cluster
+			ordered = append(ordered, cluster)
+		}
+		cluster.add(article)
+	}
+
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].first.Before(ordered[j].first)
+	})
+
+	var clusters []*storyCluster
+	for _, seed := range ordered {
+		var best *storyCluster
+		bestScore := storyCosineThreshold
+		for _, cluster := range clusters {
+			if seed.first.Sub(cluster.last) > storyMaxGap {
+				continue
+			}
+			score := cosineSimilarity(seed.centroid, cluster.centroid)
+			if score >= bestScore {
+				best = cluster
+				bestScore = score
+			}
+		}
+
+		if best == nil {
+			clusters = append(clusters, seed)
+			continue
+		}
+		best.add(seed.articles...)
+		best.mergedID = append(best.mergedID, seed.id)
+		best.mergedID = append(best.mergedID, seed.mergedID...)
+	}
+	return clusters
+}
+
+// buildStory turns a cluster into a Story, titled after the member article
+// closest to the cluster centroid.
+func buildStory(cluster *storyCluster) models.Story {
+	sort.Slice(cluster.articles, func(i, j int) bool {
+		return cluster.articles[i].PublicationDate.Before(cluster.articles[j].PublicationDate)
+	})
+
+	central := cluster.articles[0]
+	bestScore := -1.0
+	for _, article := range cluster.articles {
+		if article.DuplicateOf != nil {
+			continue
+		}
+		score := cosineSimilarity(article.VectorEmbedding, cluster.centroid)
+		if score > bestScore {
+			central = article
+			bestScore = score
+		}
+	}
+
+	summary := central.LLMSummary
+	if summary == "" {
+		summary = central.Description
+	}
+
+	story := models.Story{
+		ID:               cluster.id,
+		Title:            central.Title,
+		Summary:          summary,
+		ArticleCount:     len(cluster.articles),
+		FirstPublishedAt: cluster.first,
+		LastPublishedAt:  cluster.last,
+		UpdatedAt:        time.Now(),
+		Centroid:         cluster.centroid,
+	}
+
+	seenCategory := make(map[string]bool)
+	seenSource := make(map[string]bool)
+	for _, article := range cluster.articles {
+		story.ArticleIDs = append(story.ArticleIDs, article.ID)
+		for _, category := range article.Category {
+			if !seenCategory[category] {
+				seenCategory[category] = true
+				story.Categories = append(story.Categories, category)
+			}
+		}
+		if !seenSource[article.SourceName] {
+			seenSource[article.SourceName] = true
+			story.Sources = append(story.Sources, article.SourceName)
+		}
+	}
+	return story
+}
+
+// storeStory merges story into its stored document, together with the
+// stories merged into it, so members published before the lookback stay part
+// of it. Members are linked before merged stories are deleted, so an
+// interrupted run never leaves an article pointing at a missing story.
+func storeStory(ctx context.Context, stories, articles *mongo.Collection, story models.Story, merged []primitive.ObjectID) error {
+	memberIDs := story.ArticleIDs
+	if len(merged) > 0 {
+		cursor, err := stories.Find(ctx, bson.M{"_id": bson.M{"$in": merged}})
+		if err != nil {
+			return fmt.Errorf("failed to find merged stories: %w", err)
+		}
+		var old []models.Story
+		if err = cursor.All(ctx, &old); err != nil {
+			return fmt.Errorf("failed to decode merged stories: %w", err)
+		}
+		for _, mergedStory := range old {
+			story.ArticleIDs = append(story.ArticleIDs, mergedStory.ArticleIDs...)
+			story.Categories = append(story.Categories, mergedStory.Categories...)
+			story.Sources = append(story.Sources, mergedStory.Sources...)
+			if !mergedStory.FirstPublishedAt.IsZero() && mergedStory.FirstPublishedAt.Before(story.FirstPublishedAt) {
+				story.FirstPublishedAt = mergedStory.FirstPublishedAt
+			}
+		}
+	}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"title":              story.Title,
+			"summary":            story.Summary,
+			"centroid":           bson.M{"$literal": story.Centroid},
+			"updated_at":         story.UpdatedAt,
+			"article_ids":        unionExpr("article_ids", story.ArticleIDs),
+			"categories":         unionExpr("categories", story.Categories),
+			"sources":            unionExpr("sources", story.Sources),
+			"first_published_at": bson.M{"$min": bson.A{"$first_published_at", story.FirstPublishedAt}},
+			"last_published_at":  bson.M{"$max": bson.A{"$last_published_at", story.LastPublishedAt}},
+		}}},
+		{{Key: "$set", Value: bson.M{"article_count": bson.M{"$size": "$article_ids"}}}},
+	}
+	if _, err := stories.UpdateOne(ctx, bson.M{"_id": story.ID}, update, options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to upsert story: %w", err)
+	}
+
+	members := bson.M{"_id": bson.M{"$in": memberIDs}}
+	if len(merged) > 0 {
+		members = bson.M{"$or": []bson.M{members, {"story_id": bson.M{"$in": merged}}}}
+	}
+	_, err := articles.UpdateMany(ctx,
+		mergeFilters(members, bson.M{"story_id": bson.M{"$ne": story.ID}}),
+		bson.M{"$set": bson.M{"story_id": story.ID}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to link articles to story: %w", err)
+	}
+
+	if len(merged) > 0 {
+		if _, err := stories.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": merged}}); err != nil {
+			return fmt.Errorf("failed to remove merged stories: %w", err)
+		}
+	}
+	return nil
+}
+
+// unionExpr is an update expression adding values to the array field,
+// without duplicates.
+func unionExpr(field string, values interface{}) bson.M {
+	return bson.M{"$setUnion": bson.A{
+		bson.M{"$ifNull": bson.A{"$" + field, bson.A{}}},
+		bson.M{"$ifNull": bson.A{bson.M{"$literal": values}, bson.A{}}},
+	}}
+}
+
+// ClusterStories groups recently published articles into stories, stores them
+// in the stories collection and points each member's story_id at its story.
+// When ctx is cancelled it stops before the next story; the story being
+// written is finished first.
+func ClusterStories(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	articlesCollection := articleCollection()
+	storiesCollection := database.GetCollection("stories")
+
+	findOptions := options.Find().
+		SetSort(bson.M{"publication_date": 1}).
+		SetProjection(bson.M{
+			"_id":              1,
+			"title":            1,
+			"description":      1,
+			"llm_summary":      1,
+			"source_name":      1,
+			"category":         1,
+			"publication_date": 1,
+			"vector_embedding": 1,
+			"duplicate_of":     1,
+			"story_id":         1,
+		})
+	// Only visible articles form stories
+	filter := mergeFilters(
+		bson.M{"publication_date": bson.M{"$gte": time.Now().Add(-storyLookback)}},
+		visibilityFilter(time.Now()),
+	)
+
+	cursor, err := articlesCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return fmt.Errorf("failed to load articles for clustering: %w", err)
+	}
+	var articles []models.Article
+	if err = cursor.All(ctx, &articles); err != nil {
+		return fmt.Errorf("failed to decode articles for clustering: %w", err)
+	}
+
+	stored := 0
+	for _, cluster := range clusterArticles(articles) {
+		if len(cluster.articles) < storyMinArticles {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("story clustering interrupted after %d stories: %w", stored, err)
+		}
+		story := buildStory(cluster)
+		storeCtx, cancelStore := context.WithTimeout(context.WithoutCancel(ctx), storyWriteTimeout)
+		err := storeStory(storeCtx, storiesCollection, articlesCollection, story, cluster.mergedID)
+		cancelStore()
+		if err != nil {
+			slog.Error("Failed to store story", "story_id", story.ID.Hex(), "error", err)
+			continue
+		}
+		stored++
+	}
+
+	slog.Info("Clustered articles into stories", "articles", len(articles), "stories", stored)
+	return nil
+}
+
+// GetStories lists stories, most recently updated first.
+func GetStories(filter primitive.M, page, pageSize int64) ([]models.Story, error) {
+	collection := database.GetCollection("stories")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetSort(bson.M{"last_published_at": -1}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize).
+		SetProjection(bson.M{"centroid": 0})
+
+	cursor, err := collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find stories: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	stories := []models.Story{}
+	if err = cursor.All(ctx, &stories); err != nil {
+		return nil, fmt.Errorf("failed to decode stories: %w", err)
+	}
+	return stories, nil
+}
+
+// GetStoryTimeline returns a story with its articles ordered by publication date.
+func GetStoryTimeline(storyID primitive.ObjectID) (*dto.StoryTimelineResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var story models.Story
+	err := database.GetCollection("stories").
+		FindOne(ctx, bson.M{"_id": storyID}, options.FindOne().SetProjection(bson.M{"centroid": 0})).
+		Decode(&story)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrStoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find story: %w", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.M{"publication_date": 1}).
+		SetProjection(bson.M{"vector_embedding": 0})
+	filter := mergeFilters(bson.M{"story_id": storyID}, visibilityFilter(time.Now()), blockedSourceFilter())
+	cursor, err := articleCollection().Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find story articles: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var articles []models.Article
+	if err = cursor.All(ctx, &articles); err != nil {
+		return nil, fmt.Errorf("failed to decode story articles: %w", err)
+	}
+
+	timeline := &dto.StoryTimelineResponse{Story: story, Articles: []dto.NewsArticleResponse{}}
+	for _, article := range articles {
+		timeline.Articles = append(timeline.Articles, dto.NewNewsArticleResponse(article))
+	}
+	return timeline, nil
+}
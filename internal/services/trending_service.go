@@ -3,19 +3,23 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	database "news-api/internal/database"
+	"news-api/internal/metrics"
 	"news-api/internal/models"
+	"sort"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // enrichWithNewsData fetches full article details for trending articles.
 func enrichWithNewsData(trendingResults []bson.M) []models.TrendingArticle {
-	db := database.GetDB()
 	var enrichedArticles []models.TrendingArticle
 
 	if len(trendingResults) == 0 {
@@ -27,12 +31,12 @@ func enrichWithNewsData(trendingResults []bson.M) []models.TrendingArticle {
 	for _, res := range trendingResults {
 		articleIDStr, ok := res["_id"].(string)
 		if !ok {
-			fmt.Printf("Warning: Article ID is not a string: %v\n", res["_id"])
+			slog.Warn("Trending article ID is not a string", "article_id", res["_id"])
 			continue
 		}
 		objID, err := primitive.ObjectIDFromHex(articleIDStr)
 		if err != nil {
-			fmt.Printf("Warning: Could not convert article ID '%s' to ObjectID: %v\n", articleIDStr, err)
+			slog.Warn("Trending article ID is not an ObjectID", "article_id", articleIDStr, "error", err)
 			continue
 		}
 		articleIDs = append(articleIDs, objID)
@@ -42,10 +46,10 @@ func enrichWithNewsData(trendingResults []bson.M) []models.TrendingArticle {
 		return enrichedArticles
 	}
 
-	// Fetch articles from news_articles collection
-	cursor, err := db.Collection("news_articles").Find(context.Background(), bson.M{"_id": bson.M{"$in": articleIDs}})
+	// Fetch the trending articles
+	cursor, err := articleCollection().Find(context.Background(), bson.M{"_id": bson.M{"$in": articleIDs}})
 	if err != nil {
-		fmt.Printf("Failed to fetch news articles for enrichment: %v\n", err)
+		slog.Error("Failed to fetch articles for trending enrichment", "error", err)
 		return enrichedArticles
 	}
 	defer cursor.Close(context.Background())
@@ -54,7 +58,7 @@ func enrichWithNewsData(trendingResults []bson.M) []models.TrendingArticle {
 	for cursor.Next(context.Background()) {
 		var article models.Article
 		if err := cursor.Decode(&article); err != nil {
-			fmt.Printf("Failed to decode news article during enrichment: %v\n", err)
+			slog.Error("Failed to decode article during trending enrichment", "error", err)
 			continue
 		}
 		articleMap[article.ID] = article
@@ -65,22 +69,37 @@ func enrichWithNewsData(trendingResults []bson.M) []models.TrendingArticle {
 		if articleIDStr, ok := res["_id"].(string); ok {
 			if objID, err := primitive.ObjectIDFromHex(articleIDStr); err == nil {
 				if article, found := articleMap[objID]; found {
+					if IsSourceBlocked(article.SourceID) || !isArticleVisible(article, time.Now()) {
+						continue
+					}
+					category := ""
+					if len(article.Category) > 0 {
+						category = article.Category[0]
+					}
 					trendingArticle := models.TrendingArticle{
 						ArticleID:        articleIDStr,
-						TrendingScore:    toFloat64(res["trending_score"]),
+						TrendingScore:    toFloat64(res["trending_score"]) * SourceTrustWeight(article.SourceID),
 						InteractionCount: toInt(res["interaction_count"]),
 						RecentActivity:   toInt(res["recent_activity"]),
 						Title:            article.Title,
 						Description:      article.Description,
 						URL:              article.URL,
 						SourceName:       article.SourceName,
-						Category:         article.Category[0], // Assuming single category for simplicity
+						SourceID:         article.SourceID,
+						Category:         category, // Assuming single category for simplicity
+						LLMSummary:       article.LLMSummary,
+						PublicationDate:  article.PublicationDate,
 					}
 					enrichedArticles = append(enrichedArticles, trendingArticle)
 				}
 			}
 		}
 	}
+
+	// Trust weights can reorder the event-based ranking
+	sort.SliceStable(enrichedArticles, func(i, j int) bool {
+		return enrichedArticles[i].TrendingScore > enrichedArticles[j].TrendingScore
+	})
 	return enrichedArticles
 }
 
@@ -123,19 +142,17 @@ func CalculateTrendingScoresGlobal(duration time.Duration, limit int) []models.T
 
 	cursor, err := db.Collection("user_events").Aggregate(context.Background(), pipeline)
 	if err != nil {
-		fmt.Printf("Failed to aggregate trending scores: %v\n", err)
+		slog.Error("Failed to aggregate trending scores", "error", err)
 		return []models.TrendingArticle{}
 	}
 	defer cursor.Close(context.Background())
 
 	var results []bson.M
 	if err = cursor.All(context.Background(), &results); err != nil {
-		fmt.Printf("Failed to decode trending aggregation results: %v\n", err)
+		slog.Error("Failed to decode trending aggregation results", "error", err)
 		return []models.TrendingArticle{}
 	}
 
-	fmt.Print(&results)
-
 	return enrichWithNewsData(results)
 }
 
@@ -167,63 +184,114 @@ func toInt(v interface{}) int {
 	}
 }
 
-func GetTrendingFromCache(cacheKey string, duration time.Duration, limit int) ([]models.TrendingArticle, error) {
+// GetTrendingFromCache returns the trending articles of a configured window,
+// computing and caching them on a miss.
+func GetTrendingFromCache(window string, duration time.Duration, limit int) ([]models.TrendingArticle, error) {
 	ctx := context.Background()
+	cacheKey := trendingCacheKey(window)
 	val, err := database.Rdb.Get(ctx, cacheKey).Result()
 
 	if err == redis.Nil {
+		metrics.ObserveTrendingCache(metrics.CacheMiss)
 		articles := CalculateTrendingScoresGlobal(duration, limit)
 
 		articlesJSON, err := json.Marshal(articles)
 		if err != nil {
-			fmt.Printf("Failed to marshal trending articles for caching: %v\n", err)
+			slog.Error("Failed to marshal trending articles for caching", "error", err)
 			return articles, nil
 		}
 
-		// A cron Should work fine with this TODO
-		err = database.Rdb.Set(ctx, cacheKey, articlesJSON, 1*time.Hour).Err()
+		err = database.Rdb.Set(ctx, cacheKey, articlesJSON, settings.Trending.CacheTTL.Duration).Err()
 		if err != nil {
-			fmt.Printf("Failed to cache trending articles in Redis: %v\n", err)
+			slog.Warn("Failed to cache trending articles in Redis", "key", cacheKey, "error", err)
 		}
 
 		return articles, nil
 	} else if err != nil {
-		return nil, err
+		// Redis is optional; trending is computed directly while it is down
+		metrics.ObserveTrendingCache(metrics.CacheError)
+		slog.Warn("Failed to read trending articles from Redis", "key", cacheKey, "error", err)
+		return CalculateTrendingScoresGlobal(duration, limit), nil
 	}
+	metrics.ObserveTrendingCache(metrics.CacheHit)
 
 	var results []models.TrendingArticle
-	err = json.Unmarshal([]byte(val), &results)
-	return results, err
+	if err := json.Unmarshal([]byte(val), &results); err != nil {
+		return nil, err
+	}
+
+	// Articles hidden and sources blocked after the list was cached are
+	// dropped on read
+	visibleIDs, err := visibleArticleIDs(ctx, results)
+	if err != nil {
+		return nil, err
+	}
+	visible := results[:0]
+	for _, article := range results {
+		if visibleIDs[article.ArticleID] && !IsSourceBlocked(article.SourceID) {
+			visible = append(visible, article)
+		}
+	}
+	return visible, nil
 }
 
-func ScheduleGlobalTrendingCalculations() {
-	ctx := context.Background()
+// visibleArticleIDs returns the IDs of the cached trending articles that are
+// still stored and visible.
+func visibleArticleIDs(ctx context.Context, articles []models.TrendingArticle) (map[string]bool, error) {
+	visible := make(map[string]bool, len(articles))
+	var ids []primitive.ObjectID
+	for _, article := range articles {
+		if id, err := primitive.ObjectIDFromHex(article.ArticleID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return visible, nil
+	}
 
-	fmt.Println("this is happening !!")
+	filter := mergeFilters(primitive.M{"_id": primitive.M{"$in": ids}}, visibilityFilter(time.Now()))
+	cursor, err := articleCollection().Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check trending article visibility: %w", err)
+	}
+	defer cursor.Close(ctx)
 
-	trendingConfigs := []struct {
-		Key      string
-		Duration time.Duration
-		Limit    int
-	}{
-		{"trending:articles:6h", 6 * time.Hour, 10},
-		{"trending:articles:24h", 24 * time.Hour, 10},
-		{"trending:articles:week", 7 * 24 * time.Hour, 10},
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode trending article: %w", err)
+		}
+		visible[doc.ID.Hex()] = true
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to check trending article visibility: %w", err)
 	}
+	return visible, nil
+}
+
+// ScheduleGlobalTrendingCalculations precomputes and caches the trending
+// articles of every configured window. It is run by the cron scheduler.
+func ScheduleGlobalTrendingCalculations() error {
+	ctx := context.Background()
 
-	for _, config := range trendingConfigs {
-		articles := CalculateTrendingScoresGlobal(config.Duration, config.Limit)
+	var errs []error
+	for _, window := range TrendingWindowNames() {
+		key := trendingCacheKey(window)
+		articles := CalculateTrendingScoresGlobal(settings.Trending.Windows[window].Duration, settings.Trending.Limit)
 		articlesJSON, err := json.Marshal(articles)
 		if err != nil {
-			fmt.Printf("Failed to marshal trending articles for key %s: %v\n", config.Key, err)
+			errs = append(errs, fmt.Errorf("failed to marshal trending articles for key %s: %w", key, err))
 			continue
 		}
 
-		err = database.Rdb.Set(ctx, config.Key, articlesJSON, 1*time.Hour).Err()
+		err = database.Rdb.Set(ctx, key, articlesJSON, settings.Trending.CacheTTL.Duration).Err()
 		if err != nil {
-			fmt.Printf("Failed to cache trending articles for key %s in Redis: %v\n", config.Key, err)
+			errs = append(errs, fmt.Errorf("failed to cache trending articles for key %s: %w", key, err))
 		} else {
-			fmt.Printf("Successfully cached trending articles for key: %s\n", config.Key)
+			slog.Info("Cached trending articles", "key", key, "count", len(articles))
 		}
 	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"news-api/internal/apperror"
+	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Actor recorded for the initial version, which captures the article as it
+// was ingested.
+const ingestActor = "ingest"
+
+// Actor recorded for changes made by cron jobs.
+const schedulerActor = "scheduler"
+
+var ErrVersionNotFound = apperror.NotFound("Version not found")
+
+func snapshotOf(article models.Article) models.ArticleSnapshot {
+	return models.ArticleSnapshot{
+		Title:          article.Title,
+		Description:    article.Description,
+		Category:       article.Category,
+		RelevanceScore: article.RelevanceScore,
+		LLMSummary:     article.LLMSummary,
+		Status:         article.Status,
+		PublishAt:      article.PublishAt,
+		ExpiresAt:      article.ExpiresAt,
+		Breaking:       article.Breaking,
+	}
+}
+
+func snapshotFields(snapshot models.ArticleSnapshot) bson.M {
+	fields := bson.M{}
+	if raw, err := bson.Marshal(snapshot); err == nil {
+		_ = bson.Unmarshal(raw, &fields)
+	}
+	return fields
+}
+
+// diffSnapshots returns the fields that differ between two snapshots.
+func diffSnapshots(before, after models.ArticleSnapshot) map[string]models.FieldChange {
+	oldFields, newFields := snapshotFields(before), snapshotFields(after)
+	keys := make(map[string]bool)
+	for key := range oldFields {
+		keys[key] = true
+	}
+	for key := range newFields {
+		keys[key] = true
+	}
+
+	diff := make(map[string]models.FieldChange)
+	for key := range keys {
+		if !reflect.DeepEqual(oldFields[key], newFields[key]) {
+			diff[key] = models.FieldChange{Old: oldFields[key], New: newFields[key]}
+		}
+	}
+	return diff
+}
+
+// maxVersionAttempts bounds how often recordVersion retries when concurrent
+// changes of an article take the same version number.
+const maxVersionAttempts = 5
+
+// recordVersion stores the change from before to after. The first recorded
+// change of an article also stores its prior state as version 1, so every
+// state can be restored. Version numbers are unique per article, so a
+// concurrent change taking the same number makes it retry with the next.
+func recordVersion(ctx context.Context, before, after models.Article, action, actor string, restoredFrom int) error {
+	diff := diffSnapshots(snapshotOf(before), snapshotOf(after))
+	if len(diff) == 0 {
+		return nil
+	}
+
+	collection := database.GetCollection("article_versions")
+	for attempt := 1; ; attempt++ {
+		var last models.ArticleVersion
+		err := collection.FindOne(ctx, bson.M{"article_id": before.ID},
+			options.FindOne().SetSort(bson.M{"version": -1}).SetProjection(bson.M{"version": 1})).Decode(&last)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return fmt.Errorf("failed to find latest version: %w", err)
+		}
+
+		now := time.Now()
+		var versions []interface{}
+		if err == mongo.ErrNoDocuments {
+			last.Version = 1
+			versions = append(versions, models.ArticleVersion{
+				ArticleID: before.ID,
+				Version:   1,
+				Action:    models.VersionActionInitial,
+				Actor:     ingestActor,
+				Timestamp: now,
+				Snapshot:  snapshotOf(before),
+			})
+		}
+		versions = append(versions, models.ArticleVersion{
+			ArticleID:    before.ID,
+			Version:      last.Version + 1,
+			Action:       action,
+			Actor:        actor,
+			Timestamp:    now,
+			Diff:         diff,
+			Snapshot:     snapshotOf(after),
+			RestoredFrom: restoredFrom,
+		})
+
+		_, err = collection.InsertMany(ctx, versions)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == maxVersionAttempts {
+			return fmt.Errorf("failed to insert article version: %w", err)
+		}
+	}
+}
+
+// EnsureArticleVersionIndex creates the unique index on article versions
+// that recordVersion relies on to number concurrent changes.
+func EnsureArticleVersionIndex() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := database.GetCollection("article_versions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "article_id", Value: 1}, {Key: "version", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create article version index: %w", err)
+	}
+	return nil
+}
+
+func loadArticle(ctx context.Context, id primitive.ObjectID) (models.Article, error) {
+	var article models.Article
+	err := articleCollection().
+		FindOne(ctx, bson.M{"_id": id}, options.FindOne().SetProjection(bson.M{"vector_embedding": 0})).
+		Decode(&article)
+	if err == mongo.ErrNoDocuments {
+		return article, missingArticleError(ctx, id)
+	}
+	if err != nil {
+		return article, fmt.Errorf("failed to find article: %w", err)
+	}
+	return article, nil
+}
+
+func loadArchivedArticle(ctx context.Context, id primitive.ObjectID) (models.Article, error) {
+	var article models.Article
+	err := archiveCollection().
+		FindOne(ctx, bson.M{"_id": id}, options.FindOne().SetProjection(bson.M{"vector_embedding": 0})).
+		Decode(&article)
+	if err == mongo.ErrNoDocuments {
+		return article, ErrArticleNotFound
+	}
+	if err != nil {
+		return article, fmt.Errorf("failed to find archived article: %w", err)
+	}
+	return article, nil
+}
+
+// missingArticleError explains why an article is not in the articles
+// collection: ErrArticleArchived if it was moved to the archive,
+// ErrArticleNotFound otherwise.
+func missingArticleError(ctx context.Context, id primitive.ObjectID) error {
+	var archived struct {
+		ArchivedAt time.Time `bson:"archived_at"`
+	}
+	err := archiveCollection().
+		FindOne(ctx, bson.M{"_id": id}, options.FindOne().SetProjection(bson.M{"archived_at": 1})).
+		Decode(&archived)
+	if err == mongo.ErrNoDocuments {
+		return ErrArticleNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find archived article: %w", err)
+	}
+	return apperror.Errorf(ErrArticleArchived, "it was moved to the archive on %s and can no longer be changed",
+		archived.ArchivedAt.Format(time.DateOnly))
+}
+
+// updateArticleVersioned applies update to an article loaded as before and
+// records the resulting version. A failure to record the version is logged
+// rather than returned, since the update has already been applied.
+func updateArticleVersioned(ctx context.Context, before models.Article, update bson.M, action, actor string, restoredFrom int) (*models.Article, error) {
+	return updateArticleVersionedIf(ctx, before, bson.M{"_id": before.ID}, update, action, actor, restoredFrom)
+}
+
+// updateArticleVersionedIf is updateArticleVersioned for an update that only
+// applies while the article still matches filter.
+func updateArticleVersionedIf(ctx context.Context, before models.Article, filter, update bson.M, action, actor string, restoredFrom int) (*models.Article, error) {
+	var after models.Article
+	err := articleCollection().FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().
+			SetReturnDocument(options.After).
+			SetProjection(bson.M{"vector_embedding": 0})).
+		Decode(&after)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update article: %w", err)
+	}
+
+	if err := recordVersion(ctx, before, after, action, actor, restoredFrom); err != nil {
+		slog.WarnContext(ctx, "Failed to record article version", "article_id", before.ID.Hex(), "error", err)
+	}
+	return &after, nil
+}
+
+// textChangeFields recomputes the embedding and SimHash when the title or
+// description of an article changes.
+func textChangeFields(ctx context.Context, before models.Article, title, description string, set bson.M) error {
+	if title == before.Title && description == before.Description {
+		return nil
+	}
+	embedding, err := GetEmbeddingsfromText(ctx, title+" "+description)
+	if err != nil {
+		return fmt.Errorf("failed to get embedding: %w", err)
+	}
+	set["vector_embedding"] = embedding
+	set["simhash"] = int64(computeSimHash(title + " " + description))
+	return nil
+}
+
+// UpdateArticle edits an article's fields and records a version.
+func UpdateArticle(id primitive.ObjectID, req *dto.UpdateArticleRequest, actor string) (*dto.NewsArticleResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	before, err := loadArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	title, description := before.Title, before.Description
+	if req.Title != nil {
+		title = *req.Title
+		set["title"] = title
+	}
+	if req.Description != nil {
+		description = *req.Description
+		set["description"] = description
+	}
+	if req.Category != nil {
+		categories := NormalizeCategories(req.Category)
+		if len(categories) == 0 {
+			return nil, apperror.Errorf(ErrInvalidArticle, "category must contain at least one category")
+		}
+		set["category"] = categories
+	}
+	if req.RelevanceScore != nil {
+		set["relevance_score"] = *req.RelevanceScore
+		set["ranking_score"] = *req.RelevanceScore * SourceTrustWeight(before.SourceID)
+	}
+	if req.LLMSummary != nil {
+		set["llm_summary"] = *req.LLMSummary
+	}
+	if err := textChangeFields(ctx, before, title, description, set); err != nil {
+		return nil, err
+	}
+
+	after, err := updateArticleVersioned(ctx, before, bson.M{"$set": set}, models.VersionActionUpdate, actor, 0)
+	if err != nil {
+		return nil, err
+	}
+	response := dto.NewNewsArticleResponse(*after)
+	return &response, nil
+}
+
+// ResummarizeArticle regenerates the LLM summary of an article.
+func ResummarizeArticle(id primitive.ObjectID, actor string) (*dto.NewsArticleResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	before, err := loadArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := GetLLMSummaryFromURL(ctx, before.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LLM summary: %w", err)
+	}
+
+	after, err := updateArticleVersioned(ctx, before, bson.M{"$set": bson.M{"llm_summary": summary}},
+		models.VersionActionResummarize, actor, 0)
+	if err != nil {
+		return nil, err
+	}
+	response := dto.NewNewsArticleResponse(*after)
+	return &response, nil
+}
+
+// GetArticleVersions lists the versions of an article, newest first. Unless
+// includeHidden is set, articles readers cannot see are not found. Versions
+// of archived articles stay readable.
+func GetArticleVersions(id primitive.ObjectID, includeHidden bool) ([]models.ArticleVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	article, err := loadArticle(ctx, id)
+	if errors.Is(err, ErrArticleArchived) {
+		article, err = loadArchivedArticle(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !includeHidden && (!isArticleVisible(article, time.Now()) || IsSourceBlocked(article.SourceID)) {
+		return nil, ErrArticleNotFound
+	}
+
+	cursor, err := database.GetCollection("article_versions").Find(ctx, bson.M{"article_id": id},
+		options.Find().SetSort(bson.M{"version": -1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find article versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	versions := []models.ArticleVersion{}
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode article versions: %w", err)
+	}
+	return versions, nil
+}
+
+// RestoreArticleVersion resets an article's editable fields to the snapshot
+// of a version. The restore is itself recorded as a new version.
+func RestoreArticleVersion(id primitive.ObjectID, version int, actor string) (*dto.NewsArticleResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	before, err := loadArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var target models.ArticleVersion
+	err = database.GetCollection("article_versions").
+		FindOne(ctx, bson.M{"article_id": id, "version": version}).
+		Decode(&target)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find article version: %w", err)
+	}
+
+	snapshot := target.Snapshot
+	set := bson.M{
+		"title":           snapshot.Title,
+		"description":     snapshot.Description,
+		"category":        snapshot.Category,
+		"relevance_score": snapshot.RelevanceScore,
+		"ranking_score":   snapshot.RelevanceScore * SourceTrustWeight(before.SourceID),
+		"llm_summary":     snapshot.LLMSummary,
+	}
+	unset := bson.M{}
+	optionalFields := []struct {
+		name  string
+		value interface{}
+		empty bool
+	}{
+		{"status", snapshot.Status, snapshot.Status == ""},
+		{"publish_at", snapshot.PublishAt, snapshot.PublishAt == nil},
+		{"expires_at", snapshot.ExpiresAt, snapshot.ExpiresAt == nil},
+	}
+	for _, field := range optionalFields {
+		if field.empty {
+			unset[field.name] = ""
+		} else {
+			set[field.name] = field.value
+		}
+	}
+	if snapshot.Status != before.Status {
+		set["status_updated_at"] = time.Now()
+	}
+	if snapshot.Breaking && !before.Breaking {
+		set["breaking"] = true
+		set["breaking_at"] = time.Now()
+	} else if !snapshot.Breaking {
+		unset["breaking"] = ""
+		unset["breaking_at"] = ""
+	}
+	if err := textChangeFields(ctx, before, snapshot.Title, snapshot.Description, set); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	after, err := updateArticleVersioned(ctx, before, update, models.VersionActionRestore, actor, version)
+	if err != nil {
+		return nil, err
+	}
+	response := dto.NewNewsArticleResponse(*after)
+	return &response, nil
+}
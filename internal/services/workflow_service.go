@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"news-api/internal/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrArticleNotFound   = apperror.NotFound("Article not found")
+	ErrArticleArchived   = apperror.Conflict("Article is archived")
+	ErrInvalidTransition = apperror.Conflict("Invalid status transition")
+	ErrInvalidArticle    = apperror.Validation("Invalid article")
+)
+
+// allowedTransitions lists the states each state may move to.
+var allowedTransitions = map[string][]string{
+	models.ArticleStatusDraft:     {models.ArticleStatusScheduled, models.ArticleStatusPublished, models.ArticleStatusArchived},
+	models.ArticleStatusScheduled: {models.ArticleStatusDraft, models.ArticleStatusScheduled, models.ArticleStatusPublished, models.ArticleStatusArchived},
+	models.ArticleStatusPublished: {models.ArticleStatusDraft, models.ArticleStatusArchived},
+	models.ArticleStatusArchived:  {models.ArticleStatusDraft, models.ArticleStatusPublished},
+}
+
+// visibilityFilter matches articles readers may see at now: published (or
+// predating the workflow) and not expired.
+func visibilityFilter(now time.Time) primitive.M {
+	return primitive.M{"$and": []primitive.M{
+		{"$or": []primitive.M{
+			{"status": primitive.M{"$exists": false}},
+			{"status": models.ArticleStatusPublished},
+		}},
+		{"$or": []primitive.M{
+			{"expires_at": primitive.M{"$exists": false}},
+			{"expires_at": nil},
+			{"expires_at": primitive.M{"$gt": now}},
+		}},
+	}}
+}
+
+// vectorVisibilityFilter is visibilityFilter written with the operators a
+// $vectorSearch filter accepts. Equality with null also matches a missing
+// field.
+func vectorVisibilityFilter(now time.Time) primitive.M {
+	return primitive.M{"$and": []primitive.M{
+		{"$or": []primitive.M{
+			{"status": nil},
+			{"status": models.ArticleStatusPublished},
+		}},
+		{"$or": []primitive.M{
+			{"expires_at": nil},
+			{"expires_at": primitive.M{"$gt": now}},
+		}},
+	}}
+}
+
+// isArticleVisible is visibilityFilter for an article already in memory.
+func isArticleVisible(article models.Article, now time.Time) bool {
+	if article.Status != "" && article.Status != models.ArticleStatusPublished {
+		return false
+	}
+	return article.ExpiresAt == nil || article.ExpiresAt.After(now)
+}
+
+// validateIngestRequest rejects ingest requests whose fields cannot be
+// stored as given, which binding tags cannot express.
+func validateIngestRequest(req *dto.AddNewsRequest) error {
+	if len(NormalizeCategories(req.Category)) == 0 {
+		return apperror.Errorf(ErrInvalidArticle, "category must contain at least one category")
+	}
+	if req.PublishAt != "" && parseTime(req.PublishAt).IsZero() {
+		return apperror.Errorf(ErrInvalidArticle, "invalid publish_at %q", req.PublishAt)
+	}
+	if req.ExpiresAt != "" && parseTime(req.ExpiresAt).IsZero() {
+		return apperror.Errorf(ErrInvalidArticle, "invalid expires_at %q", req.ExpiresAt)
+	}
+	return nil
+}
+
+// applyIngestStatus sets the workflow fields of a new article from its
+// ingest request.
+func applyIngestStatus(article *models.Article, req *dto.AddNewsRequest) {
+	now := time.Now()
+	article.Status = req.Status
+	if req.PublishAt != "" {
+		if publishAt := parseTime(req.PublishAt); !publishAt.IsZero() {
+			article.PublishAt = &publishAt
+		}
+	}
+	if req.ExpiresAt != "" {
+		if expiresAt := parseTime(req.ExpiresAt); !expiresAt.IsZero() {
+			article.ExpiresAt = &expiresAt
+		}
+	}
+	if article.Status == "" {
+		article.Status = models.ArticleStatusPublished
+		if article.PublishAt != nil && article.PublishAt.After(now) {
+			article.Status = models.ArticleStatusScheduled
+		}
+	}
+	article.StatusUpdatedAt = &now
+
+	if req.Breaking {
+		article.Breaking = true
+		article.BreakingAt = &now
+	}
+}
+
+// TransitionArticleStatus moves an article to a new workflow state.
+func TransitionArticleStatus(id primitive.ObjectID, req *dto.ArticleStatusRequest, actor string) (*dto.NewsArticleResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	article, err := loadArticle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := article.Status
+	if current == "" {
+		current = models.ArticleStatusPublished
+	}
+	allowed := false
+	for _, next := range allowedTransitions[current] {
+		if next == req.Status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, apperror.Errorf(ErrInvalidTransition, "%s -> %s", current, req.Status)
+	}
+
+	now := time.Now()
+	set := bson.M{"status": req.Status, "status_updated_at": now}
+	unset := bson.M{}
+
+	switch req.Status {
+	case models.ArticleStatusScheduled:
+		publishAt := parseTime(req.PublishAt)
+		if !publishAt.After(now) {
+			return nil, apperror.Errorf(ErrInvalidTransition, "publish_at must be a future time")
+		}
+		set["publish_at"] = publishAt
+	case models.ArticleStatusPublished:
+		set["publish_at"] = now
+	}
+
+	if req.ExpiresAt != nil {
+		if *req.ExpiresAt == "" {
+			unset["expires_at"] = ""
+		} else {
+			expiresAt := parseTime(*req.ExpiresAt)
+			if expiresAt.IsZero() {
+				return nil, apperror.Errorf(ErrInvalidTransition, "invalid expires_at")
+			}
+			set["expires_at"] = expiresAt
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	updated, err := updateArticleVersioned(ctx, article, update, models.VersionActionStatus, actor, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.NewNewsArticleResponse(*updated)
+	return &response, nil
+}
+
+// PublishScheduledArticles publishes scheduled articles whose publish_at has
+// passed. It is run by the cron scheduler.
+func PublishScheduledArticles(ctx context.Context) (int64, error) {
+	now := time.Now()
+	return transitionDueArticles(ctx,
+		bson.M{"status": models.ArticleStatusScheduled, "publish_at": bson.M{"$lte": now}},
+		models.ArticleStatusPublished, now)
+}
+
+// ExpireArticles archives published articles whose expires_at has passed,
+// so the expiry shows up in their status and version history. They are
+// hidden from readers from expires_at on either way. It is run by the cron
+// scheduler.
+func ExpireArticles(ctx context.Context) (int64, error) {
+	now := time.Now()
+	return transitionDueArticles(ctx,
+		bson.M{"status": models.ArticleStatusPublished, "expires_at": bson.M{"$lte": now}},
+		models.ArticleStatusArchived, now)
+}
+
+// articleWriteTimeout bounds the update and version of one article moved by
+// the scheduler, which are not cancelled with the job.
+const articleWriteTimeout = 10 * time.Second
+
+// transitionDueArticles moves the articles matching filter to status and
+// records a version for each. Articles whose status changes in the meantime
+// are left alone. When ctx is cancelled it stops before the next article; an
+// article that was started is always both updated and versioned.
+func transitionDueArticles(ctx context.Context, filter bson.M, status string, now time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	cursor, err := articleCollection().Find(ctx, filter, options.Find().SetProjection(bson.M{"vector_embedding": 0}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find articles due for %s: %w", status, err)
+	}
+	defer cursor.Close(ctx)
+
+	var moved int64
+	var errs []error
+	for cursor.Next(ctx) {
+		var article models.Article
+		if err := cursor.Decode(&article); err != nil {
+			errs = append(errs, fmt.Errorf("failed to decode article: %w", err))
+			continue
+		}
+		update := bson.M{"$set": bson.M{"status": status, "status_updated_at": now}}
+		writeCtx, cancelWrite := context.WithTimeout(context.WithoutCancel(ctx), articleWriteTimeout)
+		_, err := updateArticleVersionedIf(writeCtx, article, bson.M{"_id": article.ID, "status": article.Status},
+			update, models.VersionActionStatus, schedulerActor, 0)
+		cancelWrite()
+		if err == ErrArticleNotFound {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("article %s: %w", article.ID.Hex(), err))
+			continue
+		}
+		moved++
+	}
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("moving articles to %s interrupted: %w", status, err))
+	} else if err := cursor.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to iterate articles due for %s: %w", status, err))
+	}
+	return moved, errors.Join(errs...)
+}
@@ -2,11 +2,14 @@ package trending_handler
 
 import (
 	"context"
-	"fmt"
+	"news-api/internal/apperror"
 	"news-api/internal/database"
+	"news-api/internal/dto"
+	"news-api/internal/models"
 	"news-api/internal/services"
 	"news-api/internal/utils"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +26,7 @@ func CreateUserEvent(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&event); err != nil {
-		utils.ErrorResponse(c, 400, "Invalid input: "+err.Error())
+		utils.Error(c, utils.InvalidInput(err))
 		return
 	}
 
@@ -43,7 +46,7 @@ func CreateUserEvent(c *gin.Context) {
 	db := database.GetDB()
 	_, err := db.Collection("user_events").InsertOne(context.Background(), userEvent)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to create event: "+err.Error())
+		utils.Error(c, apperror.Internal("Failed to create event", err))
 		return
 	}
 
@@ -53,35 +56,38 @@ func CreateUserEvent(c *gin.Context) {
 func GetTrendingNews(c *gin.Context) {
 	window := c.Query("window")
 
-	var duration time.Duration
-	switch window {
-	case "6h":
-		duration = 6 * time.Hour
-	case "24h":
-		duration = 24 * time.Hour
-	case "week":
-		duration = 7 * 24 * time.Hour
-	default:
-		utils.ErrorResponse(c, 400, "Invalid window. Use 6h, 24h, or week")
+	duration, ok := services.TrendingWindow(window)
+	if !ok {
+		utils.Error(c, apperror.Validation("Invalid window. Use "+strings.Join(services.TrendingWindowNames(), ", ")))
 		return
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultTrendingLimit())))
 
 	if err != nil || limit <= 0 {
-		utils.ErrorResponse(c, 400, "Invalid limit parameter")
+		utils.Error(c, apperror.Validation("Invalid limit parameter"))
 		return
 	}
 
-	cacheKey := fmt.Sprintf("trending:articles:%s", window)
-
-	trendingArticles, err := services.GetTrendingFromCache(cacheKey, duration, limit)
+	trendingArticles, err := services.GetTrendingFromCache(window, duration, limit)
 	if err != nil {
-		utils.ErrorResponse(c, 500, "Something Went Wrong: "+err.Error())
+		utils.Error(c, apperror.Internal("Something Went Wrong", err))
 		return // Add return here to prevent further execution on error
 	}
 
-	utils.SuccessResponse(c, gin.H{
+	pinned, err := services.GetPinnedArticles(models.PinFeedTrending, "", c.Query("region"))
+	if err != nil {
+		utils.Error(c, apperror.Internal("Failed to retrieve pinned news", err))
+		return
+	}
+	trendingArticles = services.MergePinnedTrending(trendingArticles, pinned)
+
+	var feedArticles []dto.NewsArticleResponse
+	for _, article := range trendingArticles {
+		feedArticles = append(feedArticles, dto.NewNewsArticleResponseFromTrending(article))
+	}
+
+	utils.ArticlesResponse(c, "Trending ("+window+")", feedArticles, gin.H{
 		"window":   window,
 		"articles": trendingArticles,
 	})
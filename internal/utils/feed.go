@@ -0,0 +1,311 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"news-api/internal/apperror"
+	"news-api/internal/dto"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Output formats for article listings.
+const (
+	FormatJSON     = "json"
+	FormatRSS      = "rss"
+	FormatAtom     = "atom"
+	FormatJSONFeed = "jsonfeed"
+)
+
+var feedContentTypes = map[string]string{
+	FormatRSS:      "application/rss+xml; charset=utf-8",
+	FormatAtom:     "application/atom+xml; charset=utf-8",
+	FormatJSONFeed: "application/feed+json; charset=utf-8",
+}
+
+// NegotiateFormat picks the listing format from the format query parameter,
+// falling back to the Accept header and then to the JSON envelope.
+func NegotiateFormat(c *gin.Context) (string, error) {
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		switch format {
+		case FormatJSON, FormatRSS, FormatAtom, FormatJSONFeed:
+			return format, nil
+		case "json_feed", "feed+json":
+			return FormatJSONFeed, nil
+		default:
+			return "", fmt.Errorf("unsupported format '%s', use json, rss, atom or jsonfeed", format)
+		}
+	}
+
+	switch c.NegotiateFormat("application/json", "application/rss+xml", "application/atom+xml", "application/feed+json") {
+	case "application/rss+xml":
+		return FormatRSS, nil
+	case "application/atom+xml":
+		return FormatAtom, nil
+	case "application/feed+json":
+		return FormatJSONFeed, nil
+	default:
+		return FormatJSON, nil
+	}
+}
+
+// ArticlesResponse writes a listing either as the usual success envelope
+// around data, or as an RSS 2.0, Atom 1.0 or JSON Feed 1.1 document of
+// articles titled feedTitle.
+func ArticlesResponse(c *gin.Context, feedTitle string, articles []dto.NewsArticleResponse, data interface{}) {
+	format, err := NegotiateFormat(c)
+	if err != nil {
+		Error(c, apperror.Validation(err.Error()))
+		return
+	}
+
+	switch format {
+	case FormatRSS:
+		writeXMLFeed(c, format, newRSSFeed(c, feedTitle, articles))
+	case FormatAtom:
+		writeXMLFeed(c, format, newAtomFeed(c, feedTitle, articles))
+	case FormatJSONFeed:
+		body := newJSONFeed(c, feedTitle, articles)
+		c.Header("Content-Type", feedContentTypes[format])
+		c.JSON(http.StatusOK, body)
+	default:
+		SuccessResponse(c, data)
+	}
+}
+
+func writeXMLFeed(c *gin.Context, format string, doc interface{}) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		Error(c, apperror.Internal("Failed to render feed", err))
+		return
+	}
+	c.Data(http.StatusOK, feedContentTypes[format], append([]byte(xml.Header), body...))
+}
+
+func requestURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.RequestURI()
+}
+
+// articleContent is the item body: the LLM summary, or the description for
+// articles that have none (e.g. YouTube links).
+func articleContent(article dto.NewsArticleResponse) string {
+	if article.LLMSummary != "" {
+		return article.LLMSummary
+	}
+	return article.Description
+}
+
+func feedUpdated(articles []dto.NewsArticleResponse) time.Time {
+	var updated time.Time
+	for _, article := range articles {
+		if article.PublicationDate.After(updated) {
+			updated = article.PublicationDate
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	return updated.UTC()
+}
+
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	SelfLink      rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"content:encoded,omitempty"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func newRSSFeed(c *gin.Context, title string, articles []dto.NewsArticleResponse) rssFeed {
+	self := requestURL(c)
+	feed := rssFeed{
+		Version:   "2.0",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         title,
+			Link:          self,
+			Description:   title,
+			LastBuildDate: feedUpdated(articles).Format(time.RFC1123Z),
+			SelfLink:      rssLink{Href: self, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	for _, article := range articles {
+		item := rssItem{
+			Title:       article.Title,
+			Link:        article.URL,
+			GUID:        rssGUID{IsPermaLink: "false", Value: article.ID.Hex()},
+			Description: article.Description,
+			Content:     articleContent(article),
+			Categories:  article.Category,
+		}
+		if !article.PublicationDate.IsZero() {
+			item.PubDate = article.PublicationDate.UTC().Format(time.RFC1123Z)
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return feed
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+func newAtomFeed(c *gin.Context, title string, articles []dto.NewsArticleResponse) atomFeed {
+	self := requestURL(c)
+	updated := feedUpdated(articles)
+	feed := atomFeed{
+		ID:      self,
+		Title:   title,
+		Updated: updated.Format(time.RFC3339),
+		Links:   []atomLink{{Href: self, Rel: "self", Type: "application/atom+xml"}},
+	}
+	for _, article := range articles {
+		entryDate := article.PublicationDate.UTC()
+		if article.PublicationDate.IsZero() {
+			entryDate = updated
+		}
+		entry := atomEntry{
+			ID:      "urn:news-api:article:" + article.ID.Hex(),
+			Title:   article.Title,
+			Links:   []atomLink{{Href: article.URL, Rel: "alternate"}},
+			Updated: entryDate.Format(time.RFC3339),
+			Summary: &atomText{Type: "text", Value: article.Description},
+			Content: &atomText{Type: "text", Value: articleContent(article)},
+		}
+		if !article.PublicationDate.IsZero() {
+			entry.Published = entryDate.Format(time.RFC3339)
+		}
+		if article.SourceName != "" {
+			entry.Author = &atomPerson{Name: article.SourceName}
+		}
+		for _, category := range article.Category {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+func newJSONFeed(c *gin.Context, title string, articles []dto.NewsArticleResponse) jsonFeed {
+	feed := jsonFeed{
+		Version: "https://jsonfeed.org/version/1.1",
+		Title:   title,
+		FeedURL: requestURL(c),
+		Items:   []jsonFeedItem{},
+	}
+	for _, article := range articles {
+		item := jsonFeedItem{
+			ID:          article.ID.Hex(),
+			URL:         article.URL,
+			Title:       article.Title,
+			ContentText: articleContent(article),
+			Summary:     article.Description,
+			Tags:        article.Category,
+		}
+		if !article.PublicationDate.IsZero() {
+			item.DatePublished = article.PublicationDate.UTC().Format(time.RFC3339)
+		}
+		if article.SourceName != "" {
+			item.Authors = []jsonFeedAuthor{{Name: article.SourceName}}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed
+}
@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"errors"
 	"net/http"
+	"news-api/internal/apperror"
+	"news-api/internal/logging"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 func SuccessResponse(c *gin.Context, data interface{}) {
@@ -13,9 +17,49 @@ func SuccessResponse(c *gin.Context, data interface{}) {
 	})
 }
 
-func ErrorResponse(c *gin.Context, statusCode int, message string) {
-	c.JSON(statusCode, gin.H{
+// Error writes err as an error response with a machine-readable code, a
+// message, per-field details for validation errors and the request ID, so a
+// client report can be matched with the server logs.
+//
+// The status code and message come from the apperror.Error in err's chain;
+// errors without one are internal errors whose text is not shown. The cause
+// of a server error is attached to the request for the access log.
+func Error(c *gin.Context, err error) {
+	appErr := classify(err)
+	status := appErr.Kind.Status()
+	if status >= http.StatusInternalServerError {
+		_ = c.Error(err)
+	}
+
+	body := gin.H{
 		"success": false,
-		"error":   message,
-	})
+		"code":    appErr.Kind,
+		"message": appErr.Message,
+	}
+	if len(appErr.Details) > 0 {
+		body["details"] = appErr.Details
+	}
+	if id := logging.RequestID(c.Request.Context()); id != "" {
+		body["request_id"] = id
+	}
+	c.JSON(status, body)
+}
+
+func classify(err error) *apperror.Error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		appErr = apperror.Internal("Internal server error", err)
+	}
+	// A specific error wins over a generic internal one wrapping it.
+	for appErr.Kind == apperror.KindInternal {
+		var cause *apperror.Error
+		if !errors.As(appErr.Err, &cause) {
+			break
+		}
+		appErr = cause
+	}
+	if appErr.Kind == apperror.KindInternal && mongo.IsDuplicateKeyError(err) {
+		return apperror.Conflict("Resource already exists")
+	}
+	return appErr
 }
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// trackingParams are query parameters that identify the referrer or campaign
+// rather than the content, and are dropped from canonical URLs.
+var trackingParams = map[string]bool{
+	"fbclid":     true,
+	"gclid":      true,
+	"dclid":      true,
+	"msclkid":    true,
+	"yclid":      true,
+	"igshid":     true,
+	"mc_cid":     true,
+	"mc_eid":     true,
+	"_ga":        true,
+	"ref":        true,
+	"ref_src":    true,
+	"ref_url":    true,
+	"cmpid":      true,
+	"ncid":       true,
+	"ito":        true,
+	"amp":        true,
+	"outputtype": true,
+	"si":         true,
+	"feature":    true,
+}
+
+var youtubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// CanonicalizeURL normalizes an article URL so that tracking variants, scheme
+// and host differences, AMP pages and alternate YouTube links of the same
+// content map to one string.
+func CanonicalizeURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("url is empty")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid url '%s': %w", raw, err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid url '%s': missing host", raw)
+	}
+
+	u = unwrapAMPCache(u)
+
+	host := normalizeHost(u.Hostname())
+	if id := youtubeVideoID(host, u); id != "" {
+		return "https://youtube.com/watch?v=" + id, nil
+	}
+
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host = host + ":" + port
+	}
+
+	canonicalPath := stripAMPPath(u.EscapedPath())
+	canonicalPath = path.Clean("/" + canonicalPath)
+	if canonicalPath == "/" {
+		canonicalPath = ""
+	}
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+
+	canonical := "https://" + host + canonicalPath
+	if encoded := query.Encode(); encoded != "" { // Encode sorts by key
+		canonical += "?" + encoded
+	}
+	return canonical, nil
+}
+
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, prefix := range []string{"www.", "m.", "mobile.", "amp."} {
+		if strings.HasPrefix(host, prefix) && strings.Count(host, ".") > 1 {
+			host = strings.TrimPrefix(host, prefix)
+			break
+		}
+	}
+	return host
+}
+
+// unwrapAMPCache turns Google AMP viewer and AMP cache URLs back into the
+// publisher URL they serve, e.g. google.com/amp/s/example.com/story or
+// example-com.cdn.ampproject.org/c/s/example.com/story.
+func unwrapAMPCache(u *url.URL) *url.URL {
+	host := strings.ToLower(u.Hostname())
+	p := u.Path
+
+	var inner string
+	switch {
+	case (host == "google.com" || host == "www.google.com") && strings.HasPrefix(p, "/amp/"):
+		inner = strings.TrimPrefix(p, "/amp/")
+	case strings.HasSuffix(host, ".cdn.ampproject.org"):
+		inner = strings.TrimPrefix(p, "/")
+		for _, prefix := range []string{"c/", "v/", "i/"} {
+			inner = strings.TrimPrefix(inner, prefix)
+		}
+	default:
+		return u
+	}
+
+	scheme := "http://"
+	if strings.HasPrefix(inner, "s/") {
+		inner = strings.TrimPrefix(inner, "s/")
+		scheme = "https://"
+	}
+	unwrapped, err := url.Parse(scheme + inner)
+	if err != nil || unwrapped.Host == "" {
+		return u
+	}
+	unwrapped.RawQuery = u.RawQuery
+	return unwrapped
+}
+
+// stripAMPPath removes the AMP markers publishers add to article paths:
+// a trailing or intermediate "amp" segment and ".amp"/".amp.html" suffixes.
+func stripAMPPath(p string) string {
+	segments := strings.Split(p, "/")
+	kept := segments[:0]
+	for _, segment := range segments {
+		if strings.EqualFold(segment, "amp") {
+			continue
+		}
+		kept = append(kept, segment)
+	}
+	p = strings.Join(kept, "/")
+
+	switch {
+	case strings.HasSuffix(p, ".amp.html"):
+		p = strings.TrimSuffix(p, ".amp.html") + ".html"
+	case strings.HasSuffix(p, ".amp"):
+		p = strings.TrimSuffix(p, ".amp")
+	}
+	return p
+}
+
+// youtubeVideoID extracts the video ID from youtube.com and youtu.be links,
+// returning "" for anything that is not a single-video URL.
+func youtubeVideoID(host string, u *url.URL) string {
+	var id string
+	switch host {
+	case "youtu.be":
+		id = strings.Trim(u.Path, "/")
+	case "youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		switch {
+		case segments[0] == "watch":
+			id = u.Query().Get("v")
+		case len(segments) == 2 && (segments[0] == "shorts" || segments[0] == "embed" || segments[0] == "live" || segments[0] == "v"):
+			id = segments[1]
+		}
+	}
+	if youtubeIDPattern.MatchString(id) {
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"news-api/internal/apperror"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Binding errors name fields by their JSON (or query) name, as clients see
+// them.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// InvalidInput turns a JSON decoding or binding error into a validation
+// error with one detail per offending field.
+func InvalidInput(err error) error {
+	return apperror.Validation("Invalid input", FieldErrors(err)...)
+}
+
+// FieldErrors describes each offending field of a decoding or binding error.
+func FieldErrors(err error) []apperror.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		details := make([]apperror.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			details = append(details, apperror.FieldError{Field: fe.Field(), Message: validationMessage(fe)})
+		}
+		return details
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []apperror.FieldError{{Field: typeErr.Field, Message: "must be of type " + typeErr.Type.String()}}
+	}
+	return []apperror.FieldError{{Message: err.Error()}}
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "url":
+		return "must be a URL"
+	case "min", "gte":
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		return "must be at most " + fe.Param()
+	default:
+		return fmt.Sprintf("failed '%s' validation", fe.Tag())
+	}
+}
@@ -1,34 +1,201 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
+	"net/http"
+	"news-api/internal/apperror"
+	"news-api/internal/config"
 	"news-api/internal/database"
+	"news-api/internal/handlers"
+	"news-api/internal/idempotency"
+	"news-api/internal/logging"
+	"news-api/internal/metrics"
+	"news-api/internal/middleware"
+	"news-api/internal/ratelimit"
 	"news-api/internal/routes"
 	"news-api/internal/services" // Import services package
+	"news-api/internal/tracing"
+	"news-api/internal/utils"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/robfig/cron/v3" // Import cron package
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
-	err := godotenv.Load()
+	envErr := godotenv.Load()
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Println("No .env file found, using environment variables")
+		log.Fatal(err)
+	}
+	if err := logging.Init(cfg.Logging); err != nil {
+		log.Fatal(err)
+	}
+	if envErr != nil {
+		slog.Info("No .env file found, using environment variables")
 	}
-	// Create Gin router
-	r := gin.Default()
+	services.Configure(cfg)
+	handlers.Configure(cfg)
+	ratelimit.Configure(cfg.RateLimit)
+	idempotency.Configure(cfg.Idempotency)
 
-	database.Connect()
-	database.InitRedis() // Initialize Redis client
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("Tracing setup failed", err)
+	}
 
-	// Initialize and start cron scheduler
-	c := cron.New()
-	// Schedule the global trending calculations to run every hour
-	c.AddFunc("@hourly", func() {
-		log.Println("Running scheduled global trending calculations...")
-		services.ScheduleGlobalTrendingCalculations()
+	// Create Gin router; every request gets a request ID and a server span
+	r := gin.New()
+	// The client IP, used for rate limits and logs, comes from X-Forwarded-For
+	// only behind a configured proxy
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		fatal("Invalid trusted proxies", err)
+	}
+	r.Use(middleware.RequestID(), middleware.Recovery(), otelgin.Middleware(cfg.Tracing.ServiceName))
+
+	if err := database.Connect(cfg.Mongo); err != nil {
+		fatal("MongoDB setup failed", err)
+	}
+	database.InitRedis(cfg.Redis) // Initialize Redis client
+
+	if !cfg.Auth.Enabled {
+		slog.Warn("API key authentication is disabled; every client has full access")
+	}
+	runStartupTask(func() {
+		if err := services.EnsureAPIKeyIndexes(); err != nil {
+			slog.Error("API key index setup failed", "error", err)
+		}
+	})
+
+	runStartupTask(func() {
+		if err := services.EnsureArticleVersionIndex(); err != nil {
+			slog.Error("Article version index setup failed", "error", err)
+		}
+	})
+
+	runStartupTask(func() {
+		if err := services.EnsureEventTTLIndex(); err != nil {
+			slog.Error("Event TTL index setup failed", "error", err)
+		}
+	})
+
+	// Seed the category taxonomy and normalize categories of older articles
+	runStartupTask(func() {
+		if err := services.EnsureDefaultTaxonomy(); err != nil {
+			slog.Error("Seeding category taxonomy failed", "error", err)
+			return
+		}
+		if err := services.NormalizeStoredCategories(); err != nil {
+			slog.Error("Category normalization failed", "error", err)
+		}
+	})
+
+	// Seed the source registry, then resolve sources and ranking scores of older articles
+	runStartupTask(func() {
+		if err := services.EnsureDefaultSources(); err != nil {
+			slog.Error("Seeding source registry failed", "error", err)
+			return
+		}
+		if err := services.ResolveStoredSources(); err != nil {
+			slog.Error("Source resolution failed", "error", err)
+		}
+		if err := services.BackfillRankingScores(); err != nil {
+			slog.Error("Ranking score backfill failed", "error", err)
+		}
+	})
+
+	// Canonicalize URLs of articles ingested before canonical_url existed,
+	// then let the unique index reject duplicates
+	runStartupTask(func() {
+		if err := services.BackfillCanonicalURLs(); err != nil {
+			slog.Error("Canonical URL backfill failed", "error", err)
+			return
+		}
+		if err := services.EnsureCanonicalURLIndex(); err != nil {
+			slog.Error("Canonical URL index setup failed", "error", err)
+		}
 	})
+
+	// Initialize and start cron scheduler. Jobs are wrapped so their run time
+	// and last success are exported as metrics. Long jobs stop early once
+	// jobCtx is cancelled on shutdown.
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+	c := cron.New()
+	// Schedule the global trending calculations
+	c.AddFunc(cfg.Cron.Trending, metrics.TrackCronJob("trending", func() error {
+		slog.Info("Running scheduled global trending calculations")
+		err := services.ScheduleGlobalTrendingCalculations()
+		if err != nil {
+			slog.Error("Trending calculations failed", "error", err)
+		}
+		return err
+	}))
+	// Cluster recent articles into stories
+	c.AddFunc(cfg.Cron.Clustering, metrics.TrackCronJob("clustering", func() error {
+		slog.Info("Running scheduled story clustering")
+		err := services.ClusterStories(jobCtx)
+		if err != nil {
+			slog.Error("Story clustering failed", "error", err)
+		}
+		return err
+	}))
+	// Poll RSS/Atom feed subscriptions
+	c.AddFunc(cfg.Cron.Feeds, metrics.TrackCronJob("feeds", func() error {
+		slog.Info("Polling feed subscriptions")
+		err := services.PollAllFeeds(jobCtx)
+		if err != nil {
+			slog.Error("Feed polling failed", "error", err)
+		}
+		return err
+	}))
+	// Publish scheduled articles whose publish_at has passed and archive
+	// published ones whose expires_at has
+	c.AddFunc(cfg.Cron.Publish, metrics.TrackCronJob("publish", func() error {
+		published, publishErr := services.PublishScheduledArticles(jobCtx)
+		if publishErr != nil {
+			slog.Error("Publishing scheduled articles failed", "error", publishErr)
+		}
+		if published > 0 {
+			slog.Info("Published scheduled articles", "count", published)
+		}
+		expired, expireErr := services.ExpireArticles(jobCtx)
+		if expireErr != nil {
+			slog.Error("Expiring articles failed", "error", expireErr)
+		}
+		if expired > 0 {
+			slog.Info("Archived expired articles", "count", expired)
+		}
+		return errors.Join(publishErr, expireErr)
+	}))
+	// Roll up and prune user events, and archive old articles
+	c.AddFunc(cfg.Cron.Retention, metrics.TrackCronJob("retention", func() error {
+		slog.Info("Applying event retention")
+		retentionErr := services.ApplyEventRetention(jobCtx)
+		if retentionErr != nil {
+			slog.Error("Event retention failed", "error", retentionErr)
+		}
+		archived, archiveErr := services.ArchiveOldArticles(jobCtx)
+		if archiveErr != nil {
+			slog.Error("Article archival failed", "error", archiveErr)
+		} else if archived > 0 {
+			slog.Info("Archived articles", "count", archived)
+		}
+		return errors.Join(retentionErr, archiveErr)
+	}))
 	c.Start()
 
 	// Setup all routes
@@ -36,16 +203,100 @@ func main() {
 
 	r.GET("/test-db", func(c *gin.Context) {
 		if database.Client == nil {
-			c.JSON(500, gin.H{"error": "Database not connected"})
+			utils.Error(c, apperror.New(apperror.KindUpstreamUnavailable, "Database not connected"))
 			return
 		}
 
 		c.JSON(200, gin.H{
 			"message":  "Database connected successfully!",
-			"database": "news_db",
+			"database": cfg.Mongo.Database,
 		})
 	})
 
 	// Start server
-	r.Run(":8080")
+	srv := &http.Server{Addr: cfg.Server.Addr(), Handler: r}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down")
+	case err := <-serverErr:
+		slog.Error("Server failed", "error", err)
+	}
+	stop()
+	shutdown(srv, c, cancelJobs, cfg.Server.ShutdownTimeout.Duration, shutdownTracing)
+}
+
+// fatal logs err and exits. It is used once the structured logger is set up.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// startupTasks tracks the one-off startup jobs so shutdown can wait for them.
+var startupTasks sync.WaitGroup
+
+func runStartupTask(task func()) {
+	startupTasks.Add(1)
+	go func() {
+		defer startupTasks.Done()
+		task()
+	}()
+}
+
+// shutdown drains in-flight requests, cancels and waits for running cron
+// jobs and waits for startup tasks, then closes Redis and MongoDB and flushes
+// traces. Events are written synchronously by their requests, so draining
+// the server leaves nothing buffered to flush.
+func shutdown(srv *http.Server, c *cron.Cron, cancelJobs context.CancelFunc, timeout time.Duration, shutdownTracing func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Jobs wind down while requests drain
+	cancelJobs()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Warn("HTTP server shutdown incomplete", "error", err)
+	}
+
+	select {
+	case <-c.Stop().Done():
+	case <-ctx.Done():
+		slog.Warn("Timed out waiting for running cron jobs")
+	}
+
+	tasksDone := make(chan struct{})
+	go func() {
+		startupTasks.Wait()
+		close(tasksDone)
+	}()
+	select {
+	case <-tasksDone:
+	case <-ctx.Done():
+		slog.Warn("Timed out waiting for startup tasks")
+	}
+
+	if err := database.CloseRedis(); err != nil {
+		slog.Error("Closing Redis failed", "error", err)
+	}
+	// Disconnecting needs some time of its own even after a timeout above.
+	disconnectCtx, cancelDisconnect := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelDisconnect()
+	if err := database.Disconnect(disconnectCtx); err != nil {
+		slog.Error("Disconnecting MongoDB failed", "error", err)
+	}
+	// Spans of the final requests and jobs are flushed last.
+	if err := shutdownTracing(disconnectCtx); err != nil {
+		slog.Error("Flushing traces failed", "error", err)
+	}
+	slog.Info("Shutdown complete")
 }